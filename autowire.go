@@ -30,20 +30,28 @@ type ProviderSet struct{}
 
 // NewSet creates a new provider set that includes the providers in its
 // arguments. Each argument is a function value, a provider set, a call to
-// Struct, a call to Bind, a call to Value, a call to InterfaceValue or a call
-// to FieldsOf.
+// Struct, a call to Bind, a call to BindAll, a call to Value, a call to
+// InterfaceValue, a call to FieldsOf, a call to Named, a call to Collect, a
+// call to Optional, a call to Options, a call to Default, a call to LateBind,
+// a call to Before, a call to After, a call to Lazy or a call to Qualify.
 //
 // Passing a function value to NewSet declares that the function's first
 // return value type will be provided by calling the function. The arguments
 // to the function will come from the providers for their types. As such, all
-// the function's parameters must be of non-identical types. The function may
-// optionally return an error as its last return value and a cleanup function
-// as the second return value. A cleanup function must be of type func() and is
+// the function's parameters must be of non-identical types, unless they are
+// given different qualifiers with Qualify. The function may optionally
+// return an error as its last return value and a cleanup function as the
+// second return value. A cleanup function must be of type func() and is
 // guaranteed to be called before the cleanup function of any of the
 // provider's inputs. If any provider returns an error, the injector function
 // will call all the appropriate cleanup functions and return the error from
 // the injector function.
 //
+// A method expression such as (*Config).Database is a function value whose
+// first parameter is the receiver, so it is treated as a provider in the same
+// way: the receiver is taken from the provider of its type and the generated
+// injector calls the method on it.
+//
 // Passing a ProviderSet to NewSet is the same as if the set's contents
 // were passed as arguments to NewSet directly.
 //
@@ -97,6 +105,10 @@ type Binding struct{}
 // the type of iface. iface must be a pointer to an interface type, to must be a
 // pointer to a concrete type.
 //
+// Bind is only needed if more than one provided type implements iface: if a
+// provider set has no binding for an interface type, Wire binds it to the only
+// provided type that implements it.
+//
 // Example:
 //
 //	type Fooer interface {
@@ -114,6 +126,22 @@ func Bind(iface, to interface{}) Binding {
 	return Binding{}
 }
 
+// BindAll declares that a concrete type should be used to satisfy a dependency
+// on each of the given interface types. to must be a pointer to a concrete
+// type, and iface and ifaces must be pointers to interface types that it
+// implements. It is the same as a call to Bind for each interface, and an
+// injector that needs several of them provides the concrete type once and
+// uses that one value for all of them.
+//
+// Example:
+//
+//	var MySet = autowire.NewSet(
+//		NewStore,
+//		autowire.BindAll(new(*Store), new(Reader), new(Writer)))
+func BindAll(to, iface interface{}, ifaces ...interface{}) Binding {
+	return Binding{}
+}
+
 // bindToUsePointer is detected by the autowire tool to indicate that Bind's second argument should take a pointer.
 // See https://github.com/dabbertorres/autowire/issues/120 for details.
 const bindToUsePointer = true
@@ -151,8 +179,19 @@ type StructProvider struct{}
 //
 // The first argument must be a pointer to the struct type. For a struct type
 // Foo, Wire will use field-filling to provide both Foo and *Foo. The remaining
-// arguments are field names to fill in. As a special case, if a single name "*"
-// is given, then all of the fields in the struct will be filled in.
+// arguments are field names to fill in. As a special case, if the first name
+// is "*", then all of the fields in the struct will be filled in.
+//
+// A field tagged `autowire:"-"` is never filled in. A field tagged
+// `autowire:"Name"` is filled in using the provided type named Name (or
+// pkg.Name) that is assignable to the field, instead of the provider for the
+// field's own type.
+//
+// A field name may also be given as "Name=Type", which fills in the field as
+// if it were tagged `autowire:"Type"`, replacing its tag. The names after "*"
+// override the fields they name: "Name=Type" chooses what fills in the field,
+// and "-Name" leaves the field as its zero value, as if it were tagged
+// `autowire:"-"`.
 //
 // For example:
 //
@@ -162,6 +201,7 @@ type StructProvider struct{}
 //  }
 //  var Set = autowire.NewSet(autowire.Struct(new(S), "MyFoo")) -> inject only S.MyFoo
 //  var Set = autowire.NewSet(autowire.Struct(new(S), "*")) -> inject all fields
+//  var Set = autowire.NewSet(autowire.Struct(new(S), "*", "-MyBar")) -> inject all fields but S.MyBar
 func Struct(structType interface{}, fieldNames ...string) StructProvider {
 	return StructProvider{}
 }
@@ -191,6 +231,280 @@ type StructFields struct{}
 //  If the structType argument is a pointer to a pointer to a struct, then FieldsOf
 //  additionally provides a pointer to each field type (e.g., *Foo and *Bar in the
 //  example above).
+//
+// A field name may also name a field promoted from an embedded struct, as long
+// as the selector is not ambiguous.
+//
+// As a special case, if the first name is "*", then every field declared in the
+// struct is used, except those tagged `autowire:"-"` and those that are not
+// exported from another package. The fields listed by "*" count as used if any
+// of them is used, and no two of them may have the same type. A name after "*"
+// must either be "-Name", which leaves the field out, or "Name=qualifier". With
+// or without "*", "Name=qualifier" provides the field's type qualified by
+// qualifier, as if by Named, so that it is only used for the inputs that ask
+// for it by name.
 func FieldsOf(structType interface{}, fieldNames ...string) StructFields {
 	return StructFields{}
 }
+
+// A NamedProvider is a provider with a qualifier.
+type NamedProvider struct{}
+
+// Named qualifies the type provided by a provider function with name, so that
+// a provider set can contain several providers of the same type. name must be
+// a valid Go identifier.
+//
+// A qualified type is only used for a provider function parameter of an
+// identical type that is qualified with the same name by Qualify, or for a
+// struct provider field of an identical type tagged `autowire:"name"`. Other
+// dependencies on the type do not use it.
+//
+// Example:
+//
+//	func NewPrimary() *sql.DB { /* ... */ }
+//	func NewReplica() *sql.DB { /* ... */ }
+//	func NewRepo(primary, replica *sql.DB) *Repo { /* ... */ }
+//
+//	var Set = autowire.NewSet(
+//		autowire.Named("primary", NewPrimary),
+//		autowire.Named("replica", NewReplica),
+//		autowire.Qualify(NewRepo, "primary", "replica"))
+func Named(name string, provider interface{}) NamedProvider {
+	return NamedProvider{}
+}
+
+// A QualifiedProvider is a provider with qualified parameters.
+type QualifiedProvider struct{}
+
+// Qualify qualifies the parameters of a provider function, so that they are
+// filled in by the providers qualified with Named. There is one name for each
+// parameter of provider, in order: the parameter is filled in by the provider
+// of its type qualified with that name, and an empty name leaves it
+// unqualified. Each name must be a valid Go identifier or empty, and it is an
+// error if the provider set has no provider of a parameter's type qualified
+// with its name.
+//
+// Parameters are qualified by position rather than by their names, so that
+// renaming a parameter never changes what is passed to it.
+func Qualify(provider interface{}, names ...string) QualifiedProvider {
+	return QualifiedProvider{}
+}
+
+// A Collection is a slice of provided values.
+type Collection struct{}
+
+// Collect declares that a slice type is provided by collecting every type in
+// the provider set that is assignable to the slice's element type. sliceType
+// must be a pointer to the slice type.
+//
+// The elements are in declaration order within the set: the order their
+// providers are listed in NewSet or Build, with included provider sets
+// expanded in place. Interface types bound with Bind are collected as the bound
+// type, and if both T and *T come from the same struct provider, only *T is
+// collected.
+//
+// Example:
+//
+//	func NewDispatcher(handlers []Handler) *Dispatcher { /* ... */ }
+//
+//	var Set = autowire.NewSet(
+//		NewAuthHandler,
+//		NewLogHandler,
+//		autowire.Collect(new([]Handler)),
+//		NewDispatcher)
+func Collect(sliceType interface{}) Collection {
+	return Collection{}
+}
+
+// An OptionalProvider is a provider with optional inputs.
+type OptionalProvider struct{}
+
+// Optional marks the inputs of provider with the given types as optional:
+// if nothing in the provider set provides one of them, the injector passes
+// nil instead of failing. provider is a provider function or a call to
+// Struct, Named or Optional, and each of inputTypes must be a pointer to the
+// type of one of its inputs, which must be a pointer, interface, slice, map,
+// channel or function type. Inputs that are provided are passed as usual.
+//
+// Example:
+//
+//	func NewClient(cfg *Config, tracer Tracer) *Client { /* ... */ }
+//
+//	var Set = autowire.NewSet(
+//		NewConfig,
+//		autowire.Optional(NewClient, new(Tracer)))
+func Optional(provider interface{}, inputTypes ...interface{}) OptionalProvider {
+	return OptionalProvider{}
+}
+
+// An OptionList is a slice of options for a constructor that takes functional
+// options.
+type OptionList struct{}
+
+// Options declares that a slice of an option type is provided by calling each
+// of the given option providers, in order. optionType must be a pointer to the
+// option type, and each of providers must be a provider function that returns
+// it. The option providers' inputs come from the provider set as usual.
+//
+// Only the slice is provided, so all the options can be in one set even though
+// they have the same type. A variadic provider whose last parameter is
+// ...Option is called with the slice, and if no call to Options provides the
+// slice, it is called without options. Options with no providers provides an
+// empty slice.
+//
+// Example:
+//
+//	type Option func(*Server)
+//
+//	func NewServer(cfg *Config, opts ...Option) *Server { /* ... */ }
+//	func WithTimeout(cfg *Config) Option { /* ... */ }
+//	func WithLogger(logger *Logger) Option { /* ... */ }
+//
+//	var Set = autowire.NewSet(
+//		NewConfig,
+//		NewLogger,
+//		autowire.Options(new(Option), WithTimeout, WithLogger),
+//		NewServer)
+func Options(optionType interface{}, providers ...interface{}) OptionList {
+	return OptionList{}
+}
+
+// A DefaultProvider is a provider to fall back to for an interface type.
+type DefaultProvider struct{}
+
+// Default declares provider as the fallback for inputs of an interface type.
+// iface must be a pointer to the interface type, and provider must be a
+// provider function whose output implements it. The default is only used when
+// nothing else provides the interface: a provider or Bind for the interface
+// type takes precedence, as does the only provided type that implements it.
+// If several provided types implement the interface, the default is used
+// instead of failing. A provider set may have at most one default for each
+// interface type, including the defaults of the sets it includes.
+//
+// Example:
+//
+//	var Set = autowire.NewSet(
+//		autowire.Default(new(Logger), NewNopLogger),
+//		NewServer)
+func Default(iface interface{}, provider interface{}) DefaultProvider {
+	return DefaultProvider{}
+}
+
+// A LateBinding is a setter that an injector calls after building its
+// arguments.
+type LateBinding struct{}
+
+// LateBind declares a setter that passes an interface value to a value after
+// both have been built, which allows two values to depend on each other. setter
+// is a function or method expression that takes the value to set and the
+// interface value, and returns nothing.
+//
+// After an injector builds its result, if it also built a value of the type of
+// the setter's first parameter, it calls setter with that value and the
+// interface value, building the interface value first if needed. Since the
+// first value is already built, the interface value may depend on it. The
+// provider of the first value must not take the interface, or the dependency
+// cycle remains.
+//
+// Example:
+//
+//	type Coordinator interface{ Notify(string) }
+//
+//	func NewWorker() *Worker { /* ... */ }
+//	func (w *Worker) SetCoordinator(c Coordinator) { /* ... */ }
+//	func NewHub(w *Worker) *Hub { /* ... */ } // *Hub implements Coordinator
+//
+//	var Set = autowire.NewSet(
+//		NewWorker,
+//		NewHub,
+//		autowire.Bind(new(Coordinator), new(*Hub)),
+//		autowire.LateBind((*Worker).SetCoordinator))
+func LateBind(setter interface{}) LateBinding {
+	return LateBinding{}
+}
+
+// A Hook is a function that an injector calls without using its result.
+type Hook struct{}
+
+// Before declares a hook that an injector calls before it builds anything
+// else, which is useful for setup such as registering metrics. fn is a
+// function or method expression that returns nothing or an error. The
+// injector first builds the arguments of fn from the provider set, then calls
+// fn, then builds its result.
+//
+// If fn returns a non-nil error, the injector calls the cleanup functions of
+// the values built so far and returns the error, so fn may only return an
+// error if the injector does.
+func Before(fn interface{}) Hook {
+	return Hook{}
+}
+
+// After declares a hook that an injector calls right before it returns, which
+// is useful to check invariants that span several values. fn is a function
+// or method expression that returns nothing or an error. It is called with
+// values from the provider set, which are built first if the injector's
+// result does not need them.
+//
+// If fn returns a non-nil error, the injector calls the cleanup functions of
+// the values it built and returns the error, so fn may only return an error if
+// the injector does.
+//
+// Example:
+//
+//	func (c *Config) Validate() error { /* ... */ }
+//
+//	var Set = autowire.NewSet(
+//		NewConfig,
+//		NewServer,
+//		autowire.After((*Config).Validate))
+func After(fn interface{}) Hook {
+	return Hook{}
+}
+
+// A LazyProvider is a provider of a factory function.
+type LazyProvider struct{}
+
+// Lazy declares a provider of a factory for the result of provider. provider
+// is a provider function or a call to Optional. If it returns T, the factory
+// has type func() T, and if it also returns a cleanup function or an error, so
+// does the factory: func() (T, func(), error), for example. A provider whose
+// parameter has the factory type is passed it instead of an eagerly built T.
+//
+// The injector builds the inputs of provider as usual, and the factory
+// captures them, but provider is only called when the factory is, and again
+// each time it is. The values the factory builds are the caller's: their
+// cleanup functions are returned by the factory rather than being part of the
+// injector's cleanup function.
+//
+// Example:
+//
+//	func NewReport(db *DB) (*Report, error) { /* ... */ }
+//	func NewServer(newReport func() (*Report, error)) *Server { /* ... */ }
+//
+//	var Set = autowire.NewSet(
+//		NewDB,
+//		autowire.Lazy(NewReport),
+//		NewServer)
+func Lazy(provider interface{}) LazyProvider {
+	return LazyProvider{}
+}
+
+// A Destination names the package that an injector is generated into.
+type Destination struct{}
+
+// Into declares that the injector is generated into the package with the
+// given import path instead of the package that declares it. It may only be
+// passed directly to Build, and importPath must be a string constant.
+//
+// The destination package must already exist and must not declare injectors of
+// its own. Since the generated code is outside of the declaring package, every
+// provider, value, type and field the injector uses must be exported.
+//
+// Example:
+//
+//	func InitializeServer() (*Server, error) {
+//		panic(autowire.Build(ServerSet, autowire.Into("example.com/app/cmd/server")))
+//	}
+func Into(importPath string) Destination {
+	return Destination{}
+}
@@ -18,19 +18,23 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"flag"
 	"fmt"
+	"go/format"
 	"go/token"
 	"go/types"
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/dabbertorres/autowire/gen"
 	"github.com/dabbertorres/autowire/internal/autowire"
 	"github.com/google/subcommands"
 	"github.com/pmezard/go-difflib/difflib"
@@ -83,10 +87,10 @@ func packages(f *flag.FlagSet) []string {
 	return pkgs
 }
 
-// newGenerateOptions returns an initialized autowire.GenerateOptions, possibly
-// with the Header option set.
-func newGenerateOptions(headerFile string) (*autowire.GenerateOptions, error) {
-	opts := new(autowire.GenerateOptions)
+// newGenerateOptions returns an initialized gen.Options, possibly with the
+// Header option set.
+func newGenerateOptions(headerFile string) (*gen.Options, error) {
+	opts := new(gen.Options)
 	if headerFile != "" {
 		var err error
 		opts.Header, err = ioutil.ReadFile(headerFile)
@@ -100,8 +104,22 @@ func newGenerateOptions(headerFile string) (*autowire.GenerateOptions, error) {
 type genCmd struct {
 	headerFile     string
 	prefixFileName string
+	outputFile     string
 	tags           string
 	noGoGenerate   bool
+	debug          bool
+	graphFile      string
+	reportFile     string
+	check          bool
+	dryRun         bool
+	strict         bool
+	cache          bool
+	cacheDir       string
+	maxFieldDepth  int
+	zeroFill       bool
+	annotate       bool
+	goos           string
+	goarch         string
 }
 
 func (*genCmd) Name() string { return "gen" }
@@ -113,6 +131,29 @@ func (*genCmd) Usage() string {
 	return `gen [packages]
 
   Given one or more packages, gen creates the autowire_gen.go file for each.
+  Injectors declared in _test.go files are written to autowire_gen_test.go.
+  Use -output to choose a different file name, such as wiring_gen.go; the
+  go:generate directive in the generated file passes it through.
+
+  With -cache, packages whose sources and dependencies have not changed
+  since they were last generated are skipped, and their generated files are
+  left untouched. The cache is kept in -cache-dir, which defaults to an
+  autowire directory in the user's cache directory.
+
+  With -check, nothing is written: each generated file is compared to the
+  file on disk, ignoring formatting, and gen fails with a diff of each file
+  that is out of date or missing. Use it in CI to make sure the committed
+  generated files are up to date. It disables the cache, so that every file
+  is compared.
+
+  With -n, nothing is written either: the content of each file that gen
+  would write is printed to stdout after a "-- path --" line, as in a txtar
+  archive. Unlike -check, it does not fail if the files on disk differ. It
+  disables the cache, so that every file is shown.
+
+  With -report, a JSON report of each injector is written: the values it
+  builds in order, with what provides each and where it is declared. Like
+  -debug and -graph, it disables the cache.
 
   If no packages are listed, it defaults to ".".
 `
@@ -121,8 +162,22 @@ func (*genCmd) Usage() string {
 func (cmd *genCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&cmd.headerFile, "header-file", "", "path to file to insert as a header in autowire_gen.go")
 	f.StringVar(&cmd.prefixFileName, "output-file-prefix", "", "string to prepend to output file names.")
+	f.StringVar(&cmd.outputFile, "output", "", "name of the output file in each package's directory (default \"autowire_gen.go\")")
 	f.StringVar(&cmd.tags, "tags", "", "append build tags to the default wirebuild")
 	f.BoolVar(&cmd.noGoGenerate, "no-go-generate", false, "disable including '//go:generate wire' directive in output files")
+	f.BoolVar(&cmd.debug, "debug", false, "print the resolved dependency graph of each injector to stderr")
+	f.StringVar(&cmd.graphFile, "graph", "", "path to a file to write the dependency graph of each injector to, in Graphviz DOT format")
+	f.BoolVar(&cmd.check, "check", false, "check that the generated files are up to date instead of writing them, printing a diff and failing for those that are not")
+	f.BoolVar(&cmd.dryRun, "n", false, "print the files that would be written to stdout instead of writing them")
+	f.StringVar(&cmd.reportFile, "report", "", "path to a file to write a JSON report of the providers each injector uses to")
+	f.BoolVar(&cmd.strict, "strict", false, "report members of provider sets that no injector uses as errors instead of warnings")
+	f.BoolVar(&cmd.cache, "cache", false, "skip packages whose inputs have not changed since they were last generated")
+	f.IntVar(&cmd.maxFieldDepth, "max-field-depth", 0, "maximum nesting in embedded structs of the fields named by autowire.FieldsOf, counting the field itself (0 means no limit)")
+	f.BoolVar(&cmd.zeroFill, "zero-fill-basics", false, "pass the zero value for bool, numeric and string inputs that nothing provides; a footgun meant for prototyping, since a missing dependency of such a type is then silently zero")
+	f.StringVar(&cmd.goos, "os", "", "operating system to load packages for, as in GOOS, which is also added as a suffix to the output file names (default the host's)")
+	f.StringVar(&cmd.goarch, "arch", "", "architecture to load packages for, as in GOARCH, which is also added as a suffix to the output file names (default the host's)")
+	f.BoolVar(&cmd.annotate, "annotate", false, "precede the body of each generated injector with a comment listing the order in which it builds its values and where each one comes from")
+	f.StringVar(&cmd.cacheDir, "cache-dir", "", "directory to keep the -cache in (default \"autowire\" in the user's cache directory)")
 }
 
 func (cmd *genCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
@@ -131,17 +186,81 @@ func (cmd *genCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interfa
 		log.Println("failed to get working directory: ", err)
 		return subcommands.ExitFailure
 	}
+	if cmd.check && cmd.dryRun {
+		log.Println("-check and -n can't be used together")
+		return subcommands.ExitFailure
+	}
 	opts, err := newGenerateOptions(cmd.headerFile)
 	if err != nil {
 		log.Println(err)
 		return subcommands.ExitFailure
 	}
 
+	opts.Dir = wd
 	opts.PrefixOutputFile = cmd.prefixFileName
+	opts.OutputFile = cmd.outputFile
 	opts.Tags = cmd.tags
 	opts.NoAddGenerateDirective = cmd.noGoGenerate
+	opts.Strict = cmd.strict
+	opts.MaxFieldDepth = cmd.maxFieldDepth
+	opts.ZeroFillBasics = cmd.zeroFill
+	opts.Annotate = cmd.annotate
+	opts.GOOS = cmd.goos
+	opts.GOARCH = cmd.goarch
+	if cmd.cache && !cmd.dryRun && !cmd.check {
+		opts.CacheDir = cmd.cacheDir
+		if opts.CacheDir == "" {
+			dir, err := os.UserCacheDir()
+			if err != nil {
+				log.Printf("failed to find cache directory: %v\n", err)
+				return subcommands.ExitFailure
+			}
+			opts.CacheDir = filepath.Join(dir, "autowire")
+		}
+	}
+	var graphs []gen.GraphWriter
+	if cmd.debug {
+		graphs = append(graphs, gen.NewTextGraphWriter(os.Stderr))
+	}
+	var dot *gen.DOTWriter
+	if cmd.graphFile != "" {
+		graphOut, err := os.Create(cmd.graphFile)
+		if err != nil {
+			log.Printf("failed to create graph file: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		defer graphOut.Close()
+		dot = gen.NewDOTWriter(graphOut)
+		graphs = append(graphs, dot)
+	}
+	var report *gen.ReportWriter
+	if cmd.reportFile != "" {
+		reportOut, err := os.Create(cmd.reportFile)
+		if err != nil {
+			log.Printf("failed to create report file: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		defer reportOut.Close()
+		report = gen.NewReportWriter(reportOut)
+		graphs = append(graphs, report)
+	}
+	if len(graphs) > 0 {
+		opts.Graphs = gen.MultiGraphWriter(graphs...)
+	}
 
-	outs, errs := autowire.Generate(ctx, wd, os.Environ(), packages(f), opts)
+	outs, errs := gen.Generate(ctx, packages(f), *opts)
+	if dot != nil {
+		if err := dot.Close(); err != nil {
+			log.Printf("failed to write graph file: %v\n", err)
+			return subcommands.ExitFailure
+		}
+	}
+	if report != nil {
+		if err := report.Close(); err != nil {
+			log.Printf("failed to write report file: %v\n", err)
+			return subcommands.ExitFailure
+		}
+	}
 	if len(errs) > 0 {
 		logErrors(errs)
 		log.Println("generate failed")
@@ -151,20 +270,42 @@ func (cmd *genCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interfa
 		return subcommands.ExitSuccess
 	}
 	success := true
+	outdated := false
 	for _, out := range outs {
+		for _, w := range out.Warnings {
+			log.Println("warning:", strings.Replace(w.Error(), "\n", "\n\t", -1))
+		}
 		if len(out.Errs) > 0 {
 			logErrors(out.Errs)
 			log.Printf("%s: generate failed\n", out.PkgPath)
 			success = false
 		}
+		if out.Unchanged {
+			log.Printf("%s: %s is up to date\n", out.PkgPath, out.Path)
+			continue
+		}
+		if cmd.check && len(out.Errs) == 0 {
+			if stale, err := checkOutput(out); err != nil {
+				log.Printf("%s: failed to check %s: %v\n", out.PkgPath, out.Path, err)
+				success = false
+			} else if stale {
+				outdated = true
+			}
+			continue
+		}
 		if len(out.Content) == 0 {
 			// No Autowire output. Maybe errors, maybe no Autowire directives.
 			continue
 		}
-		if err := out.Commit(); err == nil {
-			log.Printf("%s: wrote %s\n", out.PkgPath, out.OutputPath)
+		if cmd.dryRun {
+			fmt.Printf("-- %s --\n%s", out.Path, out.Content)
+			log.Printf("%s: would write %s\n", out.PkgPath, out.Path)
+			continue
+		}
+		if err := out.Write(); err == nil {
+			log.Printf("%s: wrote %s\n", out.PkgPath, out.Path)
 		} else {
-			log.Printf("%s: failed to write %s: %v\n", out.PkgPath, out.OutputPath, err)
+			log.Printf("%s: failed to write %s: %v\n", out.PkgPath, out.Path, err)
 			success = false
 		}
 	}
@@ -172,13 +313,67 @@ func (cmd *genCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interfa
 		log.Println("at least one generate failure")
 		return subcommands.ExitFailure
 	}
+	if outdated {
+		log.Println("at least one generated file is out of date; run autowire gen to update it")
+		return subcommands.ExitFailure
+	}
 	return subcommands.ExitSuccess
 }
 
+// checkOutput reports whether the file at out.Path is out of date,
+// printing a diff to stdout if it is. The file is formatted before comparing
+// it, so that only changes to the code count, and a missing file is out of
+// date. If nothing is generated for the package, the file is out of date if
+// it exists, since it is left over from injectors that were removed.
+func checkOutput(out gen.GeneratedFile) (bool, error) {
+	cur, err := ioutil.ReadFile(out.Path)
+	if len(out.Content) == 0 {
+		if os.IsNotExist(err) || out.Path == "" {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		log.Printf("%s: %s should be deleted, since the package has no injectors\n", out.PkgPath, out.Path)
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		log.Printf("%s: %s is missing\n", out.PkgPath, out.Path)
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if fmtCur, err := format.Source(cur); err == nil {
+		cur = fmtCur
+	}
+	if bytes.Equal(cur, out.Content) {
+		return false, nil
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(cur)),
+		B:        difflib.SplitLines(string(out.Content)),
+		FromFile: out.Path,
+		ToFile:   "generated",
+	})
+	if err != nil {
+		return false, err
+	}
+	fmt.Printf("%s: diff from %s:\n%s\n", out.PkgPath, out.Path, diff)
+	log.Printf("%s: %s is out of date\n", out.PkgPath, out.Path)
+	return true, nil
+}
+
 type diffCmd struct {
-	headerFile   string
-	tags         string
-	noGoGenerate bool
+	headerFile    string
+	outputFile    string
+	tags          string
+	noGoGenerate  bool
+	maxFieldDepth int
+	zeroFill      bool
+	annotate      bool
+	goos          string
+	goarch        string
 }
 
 func (*diffCmd) Name() string { return "diff" }
@@ -201,8 +396,14 @@ func (*diffCmd) Usage() string {
 
 func (cmd *diffCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&cmd.headerFile, "header-file", "", "path to file to insert as a header in autowire_gen.go")
+	f.StringVar(&cmd.outputFile, "output", "", "name of the output file in each package's directory (default \"autowire_gen.go\")")
 	f.StringVar(&cmd.tags, "tags", "", "append build tags to the default wirebuild")
 	f.BoolVar(&cmd.noGoGenerate, "no-go-generate", false, "disable including '//go:generate wire' directive in output files")
+	f.IntVar(&cmd.maxFieldDepth, "max-field-depth", 0, "maximum nesting in embedded structs of the fields named by autowire.FieldsOf, counting the field itself (0 means no limit)")
+	f.BoolVar(&cmd.zeroFill, "zero-fill-basics", false, "pass the zero value for bool, numeric and string inputs that nothing provides; a footgun meant for prototyping, since a missing dependency of such a type is then silently zero")
+	f.StringVar(&cmd.goos, "os", "", "operating system to load packages for, as in GOOS, which is also added as a suffix to the output file names (default the host's)")
+	f.StringVar(&cmd.goarch, "arch", "", "architecture to load packages for, as in GOARCH, which is also added as a suffix to the output file names (default the host's)")
+	f.BoolVar(&cmd.annotate, "annotate", false, "precede the body of each generated injector with a comment listing the order in which it builds its values and where each one comes from")
 }
 
 func (cmd *diffCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
@@ -221,10 +422,17 @@ func (cmd *diffCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interf
 		return subcommands.ExitFailure
 	}
 
+	opts.Dir = wd
+	opts.OutputFile = cmd.outputFile
 	opts.Tags = cmd.tags
+	opts.MaxFieldDepth = cmd.maxFieldDepth
+	opts.ZeroFillBasics = cmd.zeroFill
+	opts.Annotate = cmd.annotate
+	opts.GOOS = cmd.goos
+	opts.GOARCH = cmd.goarch
 	opts.NoAddGenerateDirective = !cmd.noGoGenerate
 
-	outs, errs := autowire.Generate(ctx, wd, os.Environ(), packages(f), opts)
+	outs, errs := gen.Generate(ctx, packages(f), *opts)
 	if len(errs) > 0 {
 		logErrors(errs)
 		log.Println("generate failed")
@@ -246,18 +454,18 @@ func (cmd *diffCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interf
 			continue
 		}
 		// Assumes the current file is empty if we can't read it.
-		cur, _ := ioutil.ReadFile(out.OutputPath)
+		cur, _ := ioutil.ReadFile(out.Path)
 		if diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
 			A: difflib.SplitLines(string(cur)),
 			B: difflib.SplitLines(string(out.Content)),
 		}); err == nil {
 			if diff != "" {
 				// Print the actual diff to stdout, not stderr.
-				fmt.Printf("%s: diff from %s:\n%s\n", out.PkgPath, out.OutputPath, diff)
+				fmt.Printf("%s: diff from %s:\n%s\n", out.PkgPath, out.Path, diff)
 				hadDiff = true
 			}
 		} else {
-			log.Printf("%s: failed to diff %s: %v\n", out.PkgPath, out.OutputPath, err)
+			log.Printf("%s: failed to diff %s: %v\n", out.PkgPath, out.Path, err)
 			success = false
 		}
 	}
@@ -0,0 +1,203 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gen generates the injectors of the packages that use autowire, as
+// the autowire gen command does, for tools that run it themselves instead of
+// running the command.
+package gen
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/dabbertorres/autowire/internal/autowire"
+)
+
+// A GraphWriter receives the resolved dependency graph of each injector.
+type GraphWriter = autowire.GraphWriter
+
+// An InjectorGraph is the resolved dependency graph of an injector.
+type InjectorGraph = autowire.InjectorGraph
+
+// A GraphNode is a value in an InjectorGraph.
+type GraphNode = autowire.GraphNode
+
+// NewTextGraphWriter returns a GraphWriter that writes a human-readable
+// listing of each graph to w, as autowire gen -debug does.
+func NewTextGraphWriter(w io.Writer) GraphWriter {
+	return autowire.NewTextGraphWriter(w)
+}
+
+// A DOTWriter is a GraphWriter that writes the graphs it receives to a single
+// Graphviz digraph, as autowire gen -graph does. Close must be called after
+// the last graph to finish the digraph.
+type DOTWriter = autowire.DOTWriter
+
+// NewDOTWriter returns a DOTWriter that writes to w.
+func NewDOTWriter(w io.Writer) *DOTWriter {
+	return autowire.NewDOTWriter(w)
+}
+
+// A ReportWriter is a GraphWriter that collects the graphs it receives into a
+// JSON report, as autowire gen -report does. Close must be called after the
+// last graph to write the report.
+type ReportWriter = autowire.ReportWriter
+
+// NewReportWriter returns a ReportWriter that writes to w.
+func NewReportWriter(w io.Writer) *ReportWriter {
+	return autowire.NewReportWriter(w)
+}
+
+// MultiGraphWriter returns a GraphWriter that writes each graph to all of the
+// given writers, stopping at the first error.
+func MultiGraphWriter(ws ...GraphWriter) GraphWriter {
+	return autowire.MultiGraphWriter(ws...)
+}
+
+// Options holds options for Generate. The zero value generates the files that
+// autowire gen would with no flags, in the current directory and environment.
+type Options struct {
+	// Dir is the directory the package patterns are relative to. It defaults
+	// to the current directory.
+	Dir string
+	// Env is the environment the packages are loaded in. If it is nil, the
+	// current environment is used. In case of duplicate variables, the last
+	// one in the list takes precedence.
+	Env []string
+
+	// Header is inserted at the start of each generated file.
+	Header []byte
+	// PrefixOutputFile is prepended to the name of each generated file.
+	PrefixOutputFile string
+	// OutputFile is the name of the generated file, which is written to the
+	// package's directory. It defaults to autowire_gen.go. Injectors declared
+	// in _test.go files are written to the same name with a _test.go suffix.
+	OutputFile string
+	// Tags are the build tags to load the packages with, separated by spaces.
+	Tags string
+	// NoAddGenerateDirective leaves out the //go:generate comment that
+	// regenerates each file.
+	NoAddGenerateDirective bool
+	// Strict reports the members of provider sets that no injector uses as
+	// errors instead of warnings.
+	Strict bool
+	// Annotate adds a comment to the start of each generated injector that
+	// lists the values it builds in order, and what builds each of them.
+	Annotate bool
+	// ZeroFillBasics passes the zero value for each input of a bool, numeric
+	// or string type that nothing provides, instead of reporting an error.
+	ZeroFillBasics bool
+	// GOOS and GOARCH, if not empty, are the platform to load the packages
+	// for, and are added to the names of the generated files as suffixes.
+	GOOS   string
+	GOARCH string
+	// MaxFieldDepth, if positive, limits how deeply the fields named by
+	// autowire.FieldsOf may be nested.
+	MaxFieldDepth int
+	// CacheDir, if not empty, is a directory in which Generate records the
+	// inputs of each package, so that later calls skip the packages that have
+	// not changed. The cache is not used when Graphs is set.
+	CacheDir string
+	// Graphs, if not nil, receives the resolved dependency graph of each
+	// injector. It does not affect the generated files.
+	Graphs GraphWriter
+}
+
+// A GeneratedFile is the file generated for a package by Generate.
+type GeneratedFile struct {
+	// PkgPath is the import path of the package.
+	PkgPath string
+	// Path is the path the file should be written to. It may be empty if
+	// there were errors.
+	Path string
+	// Content is the formatted source of the file. It is nil if there were
+	// errors, or if the package has no injectors or is Unchanged.
+	Content []byte
+	// Errs are the errors that kept the file from being generated.
+	Errs []error
+	// Warnings are problems that do not keep the file from being generated,
+	// such as members of provider sets that no injector uses.
+	Warnings []error
+	// Unchanged reports that the package was skipped because neither it nor
+	// its dependencies changed since the file at Path was generated with the
+	// same Options.CacheDir.
+	Unchanged bool
+}
+
+// Write writes the file to Path. It does nothing if there is no Content.
+func (f GeneratedFile) Write() error {
+	if len(f.Content) == 0 {
+		return nil
+	}
+	return ioutil.WriteFile(f.Path, f.Content, 0666)
+}
+
+// Generate generates the injectors of the packages that match pkgPatterns,
+// returning a GeneratedFile for each package, and another for the injectors
+// declared in its _test.go files if it has any. The patterns are those of the
+// go tool, described at
+// https://golang.org/cmd/go/#hdr-Package_lists_and_patterns
+//
+// Generate does not write the generated files, so the caller can inspect them
+// and decide whether to write them with GeneratedFile.Write. Only the cache in
+// Options.CacheDir is written to.
+//
+// Generate returns errors if it failed to load the packages. Errors in a
+// package's injectors are in its GeneratedFile instead.
+func Generate(ctx context.Context, pkgPatterns []string, opts Options) ([]GeneratedFile, []error) {
+	wd := opts.Dir
+	if wd == "" {
+		var err error
+		wd, err = os.Getwd()
+		if err != nil {
+			return nil, []error{err}
+		}
+	}
+	env := opts.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	outs, errs := autowire.Generate(ctx, wd, env, pkgPatterns, &autowire.GenerateOptions{
+		Header:                 opts.Header,
+		PrefixOutputFile:       opts.PrefixOutputFile,
+		OutputFile:             opts.OutputFile,
+		Tags:                   opts.Tags,
+		NoAddGenerateDirective: opts.NoAddGenerateDirective,
+		Graphs:                 opts.Graphs,
+		Strict:                 opts.Strict,
+		Annotate:               opts.Annotate,
+		ZeroFillBasics:         opts.ZeroFillBasics,
+		GOOS:                   opts.GOOS,
+		GOARCH:                 opts.GOARCH,
+		MaxFieldDepth:          opts.MaxFieldDepth,
+		CacheDir:               opts.CacheDir,
+	})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	files := make([]GeneratedFile, len(outs))
+	for i, out := range outs {
+		files[i] = GeneratedFile{
+			PkgPath:   out.PkgPath,
+			Path:      out.OutputPath,
+			Content:   out.Content,
+			Errs:      out.Errs,
+			Warnings:  out.Warnings,
+			Unchanged: out.Unchanged,
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	autowireGo, err := ioutil.ReadFile(filepath.Join("..", "autowire.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "autowire_gen_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dir, err = filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	depDir := filepath.Join(dir, "autowire")
+	files := map[string]string{
+		filepath.Join(depDir, "go.mod"):      "module github.com/dabbertorres/autowire\n",
+		filepath.Join(depDir, "autowire.go"): string(autowireGo),
+		filepath.Join(dir, "foo", "go.mod"): "module example.com/foo\n\n" +
+			"require github.com/dabbertorres/autowire v0.1.0\n" +
+			"replace github.com/dabbertorres/autowire => " + depDir + "\n",
+		filepath.Join(dir, "foo", "foo.go"): `package main
+
+func main() { println(injectMessage()) }
+
+func ProvideMessage() string { return "Hello, World!" }
+`,
+		filepath.Join(dir, "foo", "wire.go"): `//go:build wireinject
+
+package main
+
+import "github.com/dabbertorres/autowire"
+
+func injectMessage() string {
+	panic(autowire.Build(ProvideMessage))
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := Options{
+		Dir:        filepath.Join(dir, "foo"),
+		Env:        append(os.Environ(), "GO111MODULE=on", "GOFLAGS=-mod=mod", "GOPROXY=off"),
+		OutputFile: "injectors.go",
+	}
+	var text, report bytes.Buffer
+	reportWriter := NewReportWriter(&report)
+	opts.Graphs = MultiGraphWriter(NewTextGraphWriter(&text), reportWriter)
+	gens, errs := Generate(context.Background(), []string{"."}, opts)
+	if len(errs) > 0 {
+		t.Fatalf("Generate: %v", errs)
+	}
+	if len(gens) != 1 {
+		t.Fatalf("got %d files, want 1", len(gens))
+	}
+	gen := gens[0]
+	if len(gen.Errs) > 0 {
+		t.Fatalf("Generate: %v", gen.Errs)
+	}
+	if want := "example.com/foo"; gen.PkgPath != want {
+		t.Errorf("PkgPath = %q; want %q", gen.PkgPath, want)
+	}
+	if want := filepath.Join(dir, "foo", "injectors.go"); gen.Path != want {
+		t.Errorf("Path = %q; want %q", gen.Path, want)
+	}
+	if want := "string2 := ProvideMessage()"; !strings.Contains(string(gen.Content), want) {
+		t.Errorf("Content does not contain %q:\n%s", want, gen.Content)
+	}
+	if want := "injectMessage"; !strings.Contains(text.String(), want) {
+		t.Errorf("text graph does not contain %q:\n%s", want, text.String())
+	}
+	if err := reportWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := `"name": "injectMessage"`; !strings.Contains(report.String(), want) {
+		t.Errorf("report does not contain %q:\n%s", want, report.String())
+	}
+	// Generate leaves writing the file to the caller.
+	if _, err := os.Stat(gen.Path); !os.IsNotExist(err) {
+		t.Errorf("Generate wrote %s; want it left to Write (Stat error: %v)", gen.Path, err)
+	}
+	if err := gen.Write(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadFile(gen.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(gen.Content) {
+		t.Errorf("Write wrote:\n%s\nwant:\n%s", got, gen.Content)
+	}
+}
@@ -33,6 +33,11 @@ const (
 	structProvider
 	valueExpr
 	selectorExpr
+	sliceLiteral
+	nilValue
+	zeroFill
+	setterCall
+	hookCall
 )
 
 // A call represents a step of an injector function.  It may be either a
@@ -48,21 +53,50 @@ type call struct {
 	// pkg and name identify one of the following:
 	// 1) the provider to call for kind == funcProviderCall;
 	// 2) the type to construct for kind == structProvider;
-	// 3) the name to select for kind == selectorExpr.
+	// 3) the name to select for kind == selectorExpr;
+	// 4) the setter to call for kind == setterCall;
+	// 5) the hook to call for kind == hookCall.
 	pkg  *types.Package
 	name string
 
+	// pos is the position of the provider, value or field that makes this
+	// step.
+	pos token.Pos
+
 	// args is a list of arguments to call the provider with. Each element is:
 	// a) one of the givens (args[i] < len(given)),
 	// b) the result of a previous provider call (args[i] >= len(given))
 	//
-	// This will be nil for kind == valueExpr.
+	// This will be nil for kind == valueExpr and kind == nilValue, which
+	// declares a nil variable for an optional input that nothing provides,
+	// and for kind == zeroFill, which declares a zero variable for a basic
+	// type that nothing provides.
 	//
 	// If kind == selectorExpr, then the length of this slice will be 1 and the
 	// "argument" will be the value to access fields from.
+	//
+	// If kind == setterCall, then the length of this slice will be 2: the
+	// value to set, which is also out, and the interface value to pass to
+	// the setter. The step does not declare a variable.
+	//
+	// If kind == hookCall, then these are the arguments of the hook, and out
+	// is the empty tuple, since the step does not produce a value.
 	args []int
 
-	// varargs is true if the provider function is variadic.
+	// typeArgs is the list of type arguments to instantiate the provider
+	// function with, if it is generic.
+	typeArgs []types.Type
+
+	// method is true if the provider is a method called on args[0].
+	method bool
+
+	// lazy is true if the step is a factory, of type out, that calls the
+	// provider with args when it is called, as created by autowire.Lazy.
+	lazy bool
+
+	// varargs is true if the provider function is variadic and the last
+	// argument is a slice to pass as the variadic parameter. It is false if
+	// the arguments for the variadic parameter were collected from the set.
 	varargs bool
 
 	// fieldNames maps the arguments to struct field names.
@@ -77,7 +111,8 @@ type call struct {
 
 	// hasCleanup is true if the provider call returns a cleanup function.
 	hasCleanup bool
-	// hasErr is true if the provider call returns an error.
+	// hasErr is true if the provider or hook call returns an error. It is
+	// also set for kind == hookCall.
 	hasErr bool
 
 	// The following are only set for kind == valueExpr:
@@ -91,8 +126,21 @@ type call struct {
 }
 
 // solve finds the sequence of calls required to produce an output type
-// with an optional set of provided inputs.
-func solve(fset *token.FileSet, out types.Type, given *types.Tuple, set *ProviderSet) ([]call, []error) {
+// with an optional set of provided inputs. It also returns the index of the
+// output among the givens and calls, and the types that it looked up in set.
+// If zeroFillBasics is true, an input of a basic type that nothing provides
+// is the type's zero value instead of an error.
+//
+// The calls for the autowire.Before hooks in set come first, each after the
+// calls that produce its arguments. After the output is produced, the calls
+// for the late bindings in set are added: each late binding whose target was
+// produced also produces its interface type, and then calls its setter with
+// both. The calls for the autowire.After hooks in set come last.
+//
+// solve only looks types up in the maps of set itself, not in those of the
+// sets it imports, which share the object cache's hasher. The caller checks
+// that every member of set was used with verifyArgsUsed.
+func solve(fset *token.FileSet, out types.Type, given *types.Tuple, set *ProviderSet, zeroFillBasics bool) ([]call, int, *typeutil.Map, []error) {
 	ec := new(errorCollector)
 
 	// Start building the mapping of type to local variable of the given type.
@@ -107,16 +155,164 @@ func solve(fset *token.FileSet, out types.Type, given *types.Tuple, set *Provide
 	// guaranteed to be acyclic. An index value of errAbort indicates that
 	// the type was visited, but failed due to an error added to ec.
 	errAbort := errors.New("failed to visit")
-	var used []*providerSetSrc
+	used := new(typeutil.Map) // to bool
 	var calls []call
 	type frame struct {
 		t    types.Type
 		from types.Type
 		up   *frame
+		// input describes the parameter or field of the provider of from
+		// that t is for, such as "parameter cfg of NewServer", if known.
+		input string
+		// hook is the hook that t is an argument of, if t is needed by a hook
+		// instead of a provider.
+		hook *Hook
+		// late is the late binding that t is the interface of, if t is needed
+		// by its setter.
+		late *LateBinding
+	}
+	// implicit maps interface types that have no binding in set to the
+	// concrete type chosen by implicitBinding.
+	implicit := new(typeutil.Map)
+	// defaulted holds the interface types provided by a default provider.
+	defaulted := new(typeutil.Map) // to *DefaultProvider
+	srcFor := func(t types.Type) *providerSetSrc {
+		if d := defaulted.At(t); d != nil {
+			return &providerSetSrc{Provider: d.(*DefaultProvider).Provider}
+		}
+		if concrete := implicit.At(t); concrete != nil {
+			t = concrete.(types.Type)
+		}
+		return set.srcMap.At(t).(*providerSetSrc)
+	}
+	// cycle returns the dependency cycle formed if the frame f needs t, or nil
+	// if t is not being visited by f or one of the frames above it. A type
+	// that was already produced, such as the target of a late binding, never
+	// forms a cycle.
+	cycle := func(f *frame, t types.Type) []types.Type {
+		if i := index.At(t); i != nil && i != errAbort {
+			return nil
+		}
+		var path []types.Type
+		for ; f != nil; f = f.up {
+			path = append(path, f.t)
+			if types.Identical(f.t, t) {
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return append(path, t)
+			}
+		}
+		return nil
+	}
+	provided := func(t types.Type) ProvidedType {
+		if d := defaulted.At(t); d != nil {
+			return ProvidedType{t: t, p: d.(*DefaultProvider).Provider}
+		}
+		if concrete := implicit.At(t); concrete != nil {
+			t = concrete.(types.Type)
+		}
+		return set.For(t)
+	}
+	var stk []frame
+	lates := lateBindings(set)
+	lateDone := make([]bool, len(lates))
+	// nextLate adds the setter calls of the late bindings whose target and
+	// interface have been visited. If the target of one has been visited but
+	// its interface has not, it pushes the interface instead and reports true,
+	// so that the search continues from it.
+	nextLate := func() bool {
+		for i, lb := range lates {
+			target := index.At(lb.Target)
+			if lateDone[i] || target == nil {
+				continue
+			}
+			iface := index.At(lb.Iface)
+			if target != errAbort && iface == nil {
+				// The target is already built, so the interface may depend on
+				// it without forming a cycle.
+				stk = append(stk, frame{t: lb.Iface, from: lb.Target, late: lb})
+				return true
+			}
+			lateDone[i] = true
+			if target == errAbort || iface == errAbort {
+				continue
+			}
+			calls = append(calls, call{
+				kind:   setterCall,
+				pkg:    lb.Pkg,
+				name:   lb.Name,
+				pos:    lb.Pos,
+				method: lb.Method,
+				args:   []int{target.(int), iface.(int)},
+				ins:    []types.Type{lb.Target, lb.Iface},
+				out:    lb.Target,
+			})
+		}
+		return false
+	}
+	befores, afters := hooks(set)
+	hookDone := make(map[*Hook]bool)
+	// nextHook adds the call of each hook in hs whose arguments have been
+	// visited. If the arguments of one have not been visited, it pushes them
+	// instead and reports true, so that the search continues from them.
+	nextHook := func(hs []*Hook) bool {
+		for _, h := range hs {
+			if hookDone[h] {
+				continue
+			}
+			visited, failed := true, false
+			args := make([]int, len(h.Args))
+			for i := len(h.Args) - 1; i >= 0; i-- {
+				switch j := index.At(h.Args[i]); {
+				case j == nil:
+					stk = append(stk, frame{t: h.Args[i], hook: h})
+					visited = false
+				case j == errAbort:
+					failed = true
+				default:
+					args[i] = j.(int)
+				}
+			}
+			if !visited {
+				return true
+			}
+			hookDone[h] = true
+			if failed {
+				continue
+			}
+			calls = append(calls, call{
+				kind:   hookCall,
+				pkg:    h.Pkg,
+				name:   h.Name,
+				pos:    h.Pos,
+				method: h.Method,
+				args:   args,
+				ins:    h.Args,
+				out:    types.NewTuple(),
+				hasErr: h.HasErr,
+			})
+		}
+		return false
+	}
+	// next pushes the next types to visit once the stack is empty: the
+	// arguments of the Before hooks, then the output, then the types needed by
+	// the late bindings and the After hooks. It reports false once there are
+	// none left.
+	outPushed := false
+	next := func() bool {
+		if nextHook(befores) {
+			return true
+		}
+		if !outPushed {
+			outPushed = true
+			stk = append(stk, frame{t: out})
+			return true
+		}
+		return nextLate() || nextHook(afters)
 	}
-	stk := []frame{{t: out}}
 dfs:
-	for len(stk) > 0 {
+	for len(stk) > 0 || next() {
 		curr := stk[len(stk)-1]
 		stk = stk[:len(stk)-1]
 		if index.At(curr.t) != nil {
@@ -125,26 +321,87 @@ dfs:
 
 		pv := set.For(curr.t)
 		if pv.IsNil() {
-			if curr.from == nil {
-				ec.add(fmt.Errorf("no provider found for %s, output of injector", types.TypeString(curr.t, nil)))
-				index.Set(curr.t, errAbort)
+			concrete, candidates := implicitBinding(set, curr.t)
+			if concrete == nil && zeroFillBasics && (curr.from != nil || curr.hook != nil) && isZeroFillable(curr.t) {
+				index.Set(curr.t, given.Len()+len(calls))
+				calls = append(calls, call{
+					kind: zeroFill,
+					out:  curr.t,
+				})
 				continue
 			}
-			sb := new(strings.Builder)
-			fmt.Fprintf(sb, "no provider found for %s", types.TypeString(curr.t, nil))
-			for f := curr.up; f != nil; f = f.up {
-				fmt.Fprintf(sb, "\nneeded by %s in %s", types.TypeString(f.t, nil), set.srcMap.At(f.t).(*providerSetSrc).description(fset, f.t))
+			if d := set.defaultFor(curr.t); concrete == nil && d != nil {
+				// Nothing else provides the interface, so call its default
+				// provider as if the set contained it for the interface type.
+				defaulted.Set(curr.t, d)
+				used.Set(curr.t, true)
+				pv = ProvidedType{t: curr.t, p: d.Provider}
+			} else if concrete == nil {
+				sb := new(strings.Builder)
+				if len(candidates) > 1 {
+					fmt.Fprintf(sb, "multiple provided types implement %s", typeString(curr.t, nil))
+				} else {
+					fmt.Fprintf(sb, "no provider found for %s", typeString(curr.t, nil))
+				}
+				if curr.from == nil && curr.hook == nil {
+					sb.WriteString(", output of injector")
+				} else if curr.input != "" {
+					sb.WriteString(", " + curr.input)
+				}
+				if curr.from != nil && isContextType(curr.t) {
+					sb.WriteString("; add a context.Context parameter to the injector to pass it to providers")
+				}
+				if len(candidates) <= 1 {
+					sb.WriteString(pointerHint(fset, set, curr.t))
+					sb.WriteString(lazyHint(fset, set, curr.t))
+				}
+				if len(candidates) > 1 {
+					sb.WriteString("; use autowire.Bind to choose one")
+					for _, c := range candidates {
+						fmt.Fprintf(sb, "\nimplemented by %s in %s", typeString(c, nil), set.srcMap.At(c).(*providerSetSrc).description(fset, c))
+					}
+				}
+				root := &curr
+				for f := curr.up; f != nil; f = f.up {
+					fmt.Fprintf(sb, "\nneeded by %s in %s", typeString(f.t, nil), srcFor(f.t).description(fset, f.t))
+					root = f
+				}
+				if root.hook != nil {
+					fmt.Fprintf(sb, "\nneeded by %s", root.hook.description(fset))
+				} else if root.late != nil {
+					fmt.Fprintf(sb, "\nneeded by %s", root.late.description(fset))
+				}
+				if len(candidates) == 0 && !isContextType(curr.t) {
+					for _, t := range similarTypes(set, curr.t) {
+						fmt.Fprintf(sb, "\nsimilar to %s in %s", typeString(t, nil), set.srcMap.At(t).(*providerSetSrc).description(fset, t))
+					}
+					if res, ok := lazyFactoryResult(curr.t); ok {
+						fmt.Fprintf(sb, "\nto provide it, add a provider such as func(...) %s and pass it to autowire.Lazy", typeString(res, nil))
+					} else {
+						fmt.Fprintf(sb, "\nto provide it, add a provider such as func(...) %s", typeString(curr.t, nil))
+					}
+				}
+				ec.add(errors.New(sb.String()))
+				index.Set(curr.t, errAbort)
+				continue
+			} else {
+				// Bind the interface to the only type that implements it, as if
+				// the set contained an autowire.Bind for it.
+				implicit.Set(curr.t, concrete)
+				pv = set.For(concrete)
 			}
-			ec.add(errors.New(sb.String()))
-			index.Set(curr.t, errAbort)
-			continue
+		} else {
+			used.Set(curr.t, true)
 		}
-		src := set.srcMap.At(curr.t).(*providerSetSrc)
-		used = append(used, src)
 		if concrete := pv.Type(); !types.Identical(concrete, curr.t) {
 			// Interface binding does not create a call.
 			i := index.At(concrete)
 			if i == nil {
+				if path := cycle(&curr, concrete); path != nil {
+					ec.add(cycleError(fset, path, provided))
+					index.Set(curr.t, errAbort)
+					continue
+				}
 				stk = append(stk, curr, frame{t: concrete, from: curr.t, up: &curr})
 				continue
 			}
@@ -152,34 +409,98 @@ dfs:
 			continue
 		}
 
-		switch pv := set.For(curr.t); {
+		switch {
 		case pv.IsArg():
 			// Continue, already added to stk.
 		case pv.IsProvider():
 			p := pv.Provider()
+			ins := make([]types.Type, len(p.Args))
+			for i := range p.Args {
+				t, err := inputType(set.providerMap, p.Args[i])
+				if err != nil {
+					if p.IsStruct {
+						err = fmt.Errorf("struct provider %s: field %s: %v", p.Name, p.Args[i].FieldName, err)
+					} else if name := p.Args[i].ParamName; name != "" && name != "_" {
+						err = fmt.Errorf("provider %s: parameter %s: %v", p.Name, name, err)
+					} else {
+						err = fmt.Errorf("provider %s: parameter %d: %v", p.Name, i, err)
+					}
+					ec.add(notePosition(fset.Position(p.Pos), err))
+					index.Set(curr.t, errAbort)
+					continue dfs
+				}
+				ins[i] = t
+			}
+			if p.IsCollect {
+				ins = variadicArgs(set, curr.t.(*types.Slice).Elem(), curr.t)
+			}
+			// An optional input that nothing provides is passed as nil, so it is
+			// not visited.
+			absent := make([]bool, len(ins))
+			for i := range p.Args {
+				if !p.Args[i].Optional || index.At(ins[i]) != nil || !set.For(ins[i]).IsNil() || set.defaultFor(ins[i]) != nil {
+					continue
+				}
+				if concrete, candidates := implicitBinding(set, ins[i]); concrete == nil && len(candidates) == 0 {
+					absent[i] = true
+				}
+			}
+			varargs := p.Varargs
+			if varargs && set.For(ins[len(ins)-1]).IsNil() && !absent[len(ins)-1] {
+				// Nothing provides the slice type, so pass the provided types
+				// that can be used as an element instead.
+				elem := ins[len(ins)-1].(*types.Slice).Elem()
+				fixed := ins[:len(ins)-1]
+				ins = append(fixed[:len(fixed):len(fixed)], varargsArgs(set, elem, curr.t, fixed)...)
+				varargs = false
+			}
 			// Ensure that all argument types have been visited. If not, push them
 			// on the stack in reverse order so that calls are added in argument
 			// order.
 			visitedArgs := true
-			for i := len(p.Args) - 1; i >= 0; i-- {
-				a := p.Args[i]
-				if index.At(a.Type) == nil {
+			for i := len(ins) - 1; i >= 0; i-- {
+				if i < len(absent) && absent[i] {
+					continue
+				}
+				if path := cycle(&curr, ins[i]); path != nil {
+					ec.add(cycleError(fset, path, provided))
+					index.Set(curr.t, errAbort)
+					continue dfs
+				}
+				if index.At(ins[i]) == nil {
 					if visitedArgs {
 						// Make sure to re-visit this type after visiting all arguments.
 						stk = append(stk, curr)
 						visitedArgs = false
 					}
-					stk = append(stk, frame{t: a.Type, from: curr.t, up: &curr})
+					next := frame{t: ins[i], from: curr.t, up: &curr}
+					switch {
+					case i >= len(p.Args):
+					case p.IsStruct:
+						next.input = fmt.Sprintf("field %s of %s", p.Args[i].FieldName, typeString(curr.t, nil))
+					case !p.IsCollect && !p.IsOptions && p.Args[i].ParamName != "" && p.Args[i].ParamName != "_":
+						next.input = fmt.Sprintf("parameter %s of %s", p.Args[i].ParamName, p.Name)
+					}
+					stk = append(stk, next)
 				}
 			}
 			if !visitedArgs {
 				continue
 			}
-			args := make([]int, len(p.Args))
-			ins := make([]types.Type, len(p.Args))
-			for i := range p.Args {
-				ins[i] = p.Args[i].Type
-				v := index.At(p.Args[i].Type)
+			args := make([]int, len(ins))
+			for i := range ins {
+				if i < len(absent) && absent[i] {
+					// The nil variable is not added to index, since other
+					// inputs of the type must still be provided.
+					args[i] = given.Len() + len(calls)
+					calls = append(calls, call{
+						kind: nilValue,
+						out:  ins[i],
+						pos:  p.Pos,
+					})
+					continue
+				}
+				v := index.At(ins[i])
 				if v == errAbort {
 					index.Set(curr.t, errAbort)
 					continue dfs
@@ -195,12 +516,19 @@ dfs:
 					fieldNames = append(fieldNames, arg.FieldName)
 				}
 			}
+			if p.IsCollect || p.IsOptions {
+				kind = sliceLiteral
+			}
 			calls = append(calls, call{
 				kind:       kind,
 				pkg:        p.Pkg,
 				name:       p.Name,
+				pos:        p.Pos,
 				args:       args,
-				varargs:    p.Varargs,
+				typeArgs:   p.TypeArgs,
+				method:     p.IsMethod,
+				lazy:       p.IsLazy,
+				varargs:    varargs,
 				fieldNames: fieldNames,
 				ins:        ins,
 				out:        curr.t,
@@ -213,6 +541,7 @@ dfs:
 			calls = append(calls, call{
 				kind:          valueExpr,
 				out:           curr.t,
+				pos:           v.Pos,
 				valueExpr:     v.expr,
 				valueTypeInfo: v.info,
 			})
@@ -221,6 +550,11 @@ dfs:
 			if index.At(f.Parent) == nil {
 				// Fields have one dependency which is the parent struct. Make
 				// sure to visit it first if it is not already visited.
+				if path := cycle(&curr, f.Parent); path != nil {
+					ec.add(cycleError(fset, path, provided))
+					index.Set(curr.t, errAbort)
+					continue
+				}
 				stk = append(stk, curr, frame{t: f.Parent, from: curr.t, up: &curr})
 				continue
 			}
@@ -239,6 +573,7 @@ dfs:
 				kind:       selectorExpr,
 				pkg:        f.Pkg,
 				name:       f.Name,
+				pos:        f.Pos,
 				out:        curr.t,
 				args:       args,
 				ptrToField: ptrToField,
@@ -248,27 +583,297 @@ dfs:
 		}
 	}
 	if len(ec.errors) > 0 {
-		return nil, ec.errors
+		return nil, 0, nil, ec.errors
 	}
-	if errs := verifyArgsUsed(set, used); len(errs) > 0 {
-		return nil, errs
+	return calls, index.At(out).(int), used, nil
+}
+
+// lateBindings returns the late bindings of set and the sets it imports, with
+// those of imported sets first.
+func lateBindings(set *ProviderSet) []*LateBinding {
+	var lates []*LateBinding
+	seen := make(map[*LateBinding]bool)
+	visited := make(map[*ProviderSet]bool)
+	var visit func(set *ProviderSet)
+	visit = func(set *ProviderSet) {
+		if visited[set] {
+			return
+		}
+		visited[set] = true
+		for _, imp := range set.Imports {
+			visit(imp)
+		}
+		for _, lb := range set.LateBindings {
+			if !seen[lb] {
+				seen[lb] = true
+				lates = append(lates, lb)
+			}
+		}
 	}
-	return calls, nil
+	visit(set)
+	return lates
 }
 
-// verifyArgsUsed ensures that all of the arguments in set were used during solve.
-func verifyArgsUsed(set *ProviderSet, used []*providerSetSrc) []error {
-	var errs []error
-	for _, imp := range set.Imports {
-		found := false
-		for _, u := range used {
-			if u.Import == imp {
-				found = true
+// hooks returns the autowire.Before and autowire.After hooks of set and the
+// sets it imports, with those of imported sets first.
+func hooks(set *ProviderSet) (befores, afters []*Hook) {
+	seen := make(map[*Hook]bool)
+	visited := make(map[*ProviderSet]bool)
+	var visit func(set *ProviderSet)
+	visit = func(set *ProviderSet) {
+		if visited[set] {
+			return
+		}
+		visited[set] = true
+		for _, imp := range set.Imports {
+			visit(imp)
+		}
+		for _, h := range set.Hooks {
+			if seen[h] {
+				continue
+			}
+			seen[h] = true
+			if h.After {
+				afters = append(afters, h)
+			} else {
+				befores = append(befores, h)
+			}
+		}
+	}
+	visit(set)
+	return befores, afters
+}
+
+// implicitBinding finds the type to use for a dependency on the interface
+// type iface when set does not contain a binding for it. It returns the only
+// non-interface type provided by set that implements iface. If there is not
+// exactly one such type, it returns nil and the sorted list of candidates.
+//
+// A provider that produces both T and *T (such as a struct provider) counts
+// as a single candidate; if both implement iface, *T is chosen. Empty
+// interfaces and context.Context are never bound implicitly.
+func implicitBinding(set *ProviderSet, iface types.Type) (types.Type, []types.Type) {
+	methods, ok := iface.Underlying().(*types.Interface)
+	if !ok || methods.Empty() || isContextType(iface) {
+		return nil, nil
+	}
+	var candidates []types.Type
+	for _, t := range set.Outputs() {
+		if _, isIface := t.Underlying().(*types.Interface); isIface {
+			continue
+		}
+		if types.Implements(t, methods) {
+			candidates = append(candidates, t)
+		}
+	}
+	candidates = preferPointers(set.providerMap, candidates)
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	sortTypes(candidates)
+	return nil, candidates
+}
+
+// variadicArgs returns the types to pass for a variadic parameter with
+// element type elem of the provider for out, when set does not provide a slice
+// to pass instead. These are all of the types provided by set that are
+// assignable to elem, in the order they are listed in set. Interface types
+// provided by a binding are left out in favor of the bound type, as are
+// out itself and T when *T is provided by the same source.
+func variadicArgs(set *ProviderSet, elem, out types.Type) []types.Type {
+	var args []types.Type
+	seen := new(typeutil.Map)
+	for _, t := range set.order {
+		if seen.At(t) != nil {
+			continue
+		}
+		seen.Set(t, true)
+		pt := set.For(t)
+		if pt.IsNil() || !types.Identical(pt.Type(), t) || types.Identical(t, out) {
+			continue
+		}
+		if _, _, ok := qualifier(t); ok {
+			continue
+		}
+		if types.AssignableTo(t, elem) {
+			args = append(args, t)
+		}
+	}
+	return preferPointers(set.providerMap, args)
+}
+
+// varargsArgs returns the types to pass for a variadic parameter with element
+// type elem of the provider for out, whose other parameters are fixed, when
+// set does not provide a slice to pass instead. Unlike autowire.Collect, which
+// asks for it, this only collects the outputs of providers, leaving out the
+// injector's arguments, values and fields as well as the types passed as
+// fixed, and only for an element type that is an interface with methods, so
+// that parameters such as ...interface{} or ...string are left empty.
+func varargsArgs(set *ProviderSet, elem, out types.Type, fixed []types.Type) []types.Type {
+	iface, ok := elem.Underlying().(*types.Interface)
+	if !ok || iface.Empty() {
+		return nil
+	}
+	var args []types.Type
+	for _, t := range variadicArgs(set, elem, out) {
+		if !set.For(t).IsProvider() || containsType(fixed, t) {
+			continue
+		}
+		args = append(args, t)
+	}
+	return args
+}
+
+// containsType reports whether ts has a type identical to t.
+func containsType(ts []types.Type, t types.Type) bool {
+	for _, u := range ts {
+		if types.Identical(u, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// inputType returns the type used to satisfy the provider input in, given the
+// providerMap of the set the provider is used in. If the input is a parameter
+// qualified by autowire.Qualify, this is the qualified type for in.Type, which
+// the set must provide. Otherwise, it is the qualified type for in.Type if the
+// input is a struct field with an `autowire:"Name"` tag and the set has a
+// provider of in.Type qualified by Name with autowire.Named, or else the
+// provided type named Name that is assignable to the field, and failing all
+// that, in.Type. Only identifiers are looked up as qualifiers, since Named
+// only gives those.
+func inputType(providerMap *typeutil.Map, in ProviderInput) (types.Type, error) {
+	if in.Qualifier != "" {
+		if t := qualifiedKey(providerMap, in.Qualifier, in.Type); t != nil {
+			return t, nil
+		}
+		return nil, fmt.Errorf("no provider of %s is qualified as %q with autowire.Named", typeString(in.Type, nil), in.Qualifier)
+	}
+	if in.TypeName == "" {
+		return in.Type, nil
+	}
+	if token.IsIdentifier(in.TypeName) {
+		if t := qualifiedKey(providerMap, in.TypeName, in.Type); t != nil {
+			return t, nil
+		}
+	}
+	var candidates []types.Type
+	for _, t := range providerMap.Keys() {
+		if hasTypeName(t, in.TypeName) && types.AssignableTo(t, in.Type) {
+			candidates = append(candidates, t)
+		}
+	}
+	candidates = preferPointers(providerMap, candidates)
+	sortTypes(candidates)
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("no provider of %s is named %q, and no provided type named %q is assignable to it", typeString(in.Type, nil), in.TypeName, in.TypeName)
+	case 1:
+		return candidates[0], nil
+	}
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = typeString(c, nil)
+	}
+	return nil, fmt.Errorf("multiple provided types named %q are assignable to %s: %s", in.TypeName, typeString(in.Type, nil), strings.Join(names, ", "))
+}
+
+// qualifiedKey returns the qualified type for t qualified by name if it is a
+// key of providerMap, or nil otherwise.
+func qualifiedKey(providerMap *typeutil.Map, name string, t types.Type) types.Type {
+	for _, k := range providerMap.Keys() {
+		if q, qt, ok := qualifier(k); ok && q == name && types.Identical(qt, t) {
+			return k
+		}
+	}
+	return nil
+}
+
+// hasTypeName reports whether t, or the type t points to, is a named type with
+// the given name. The name may be qualified with the package name.
+func hasTypeName(t types.Type, name string) bool {
+	if _, _, ok := qualifier(t); ok {
+		return false
+	}
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	n, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := n.Obj()
+	if obj.Name() == name {
+		return true
+	}
+	return obj.Pkg() != nil && obj.Pkg().Name()+"."+obj.Name() == name
+}
+
+// preferPointers removes T from candidates if *T is also a candidate and both
+// are provided by the same source in providerMap, such as a struct provider.
+// It returns the remaining candidates in their original order.
+func preferPointers(providerMap *typeutil.Map, candidates []types.Type) []types.Type {
+	for i := 0; i < len(candidates); i++ {
+		ptr, ok := candidates[i].(*types.Pointer)
+		if !ok {
+			continue
+		}
+		ptrSrc := providerMap.At(ptr).(*ProvidedType)
+		for j := 0; j < len(candidates); j++ {
+			if types.Identical(ptr.Elem(), candidates[j]) && ptrSrc.sameSource(*providerMap.At(candidates[j]).(*ProvidedType)) {
+				candidates = append(candidates[:j], candidates[j+1:]...)
+				if j < i {
+					i--
+				}
 				break
 			}
 		}
+	}
+	return candidates
+}
+
+// sortTypes sorts ts by type string.
+func sortTypes(ts []types.Type) {
+	sort.Slice(ts, func(i, j int) bool {
+		return typeString(ts[i], nil) < typeString(ts[j], nil)
+	})
+}
+
+// isContextType reports whether t is context.Context. A context is expected to
+// be passed in as an injector argument rather than bound implicitly.
+func isContextType(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := n.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
+
+// verifyArgsUsed ensures that all of the arguments in set were used during
+// solve. used holds the types that solve looked up in set.
+//
+// An argument counts as used if it provides one of the used types, even if a
+// duplicate of it reachable through another argument was recorded in the
+// set's srcMap.
+func verifyArgsUsed(set *ProviderSet, used *typeutil.Map) []error {
+	usedBy := func(match func(t types.Type, pt ProvidedType) bool) bool {
+		return usedBy(set, used, match)
+	}
+	var errs []error
+	for _, imp := range set.Imports {
+		found := usedBy(func(t types.Type, pt ProvidedType) bool {
+			if d := set.defaultFor(t); pt.IsNil() && d != nil {
+				return d == imp.defaultFor(t)
+			}
+			ipt, ok := imp.providerMap.At(t).(*ProvidedType)
+			return ok && isDuplicate(ipt, &pt)
+		})
 		if !found {
-			if imp.VarName == "" {
+			if list := optionsList(imp); list != nil {
+				errs = append(errs, fmt.Errorf("unused autowire.Options of type %s", typeString(list.Out[0], nil)))
+			} else if imp.VarName == "" {
 				errs = append(errs, errors.New("unused provider set"))
 			} else {
 				errs = append(errs, fmt.Errorf("unused provider set %q", imp.VarName))
@@ -276,49 +881,49 @@ func verifyArgsUsed(set *ProviderSet, used []*providerSetSrc) []error {
 		}
 	}
 	for _, p := range set.Providers {
-		found := false
-		for _, u := range used {
-			if u.Provider == p {
-				found = true
-				break
-			}
-		}
-		if !found {
+		found := usedBy(func(_ types.Type, pt ProvidedType) bool {
+			return pt.p != nil && sameProvider(pt.p, p)
+		})
+		if !found && (p.IsCollect || p.IsOptions) {
+			errs = append(errs, fmt.Errorf("unused autowire.%s of type %s", p.Name, typeString(p.Out[0], nil)))
+		} else if !found {
 			errs = append(errs, fmt.Errorf("unused provider %q", p.Pkg.Name()+"."+p.Name))
 		}
 	}
 	for _, v := range set.Values {
-		found := false
-		for _, u := range used {
-			if u.Value == v {
-				found = true
-				break
-			}
-		}
+		found := usedBy(func(_ types.Type, pt ProvidedType) bool {
+			return pt.v == v
+		})
 		if !found {
-			errs = append(errs, fmt.Errorf("unused value of type %s", types.TypeString(v.Out, nil)))
+			errs = append(errs, fmt.Errorf("unused value of type %s", typeString(v.Out, nil)))
 		}
 	}
 	for _, b := range set.Bindings {
-		found := false
-		for _, u := range used {
-			if u.Binding == b {
-				found = true
-				break
-			}
-		}
+		found := usedBy(func(t types.Type, _ ProvidedType) bool {
+			return types.Identical(t, b.Iface)
+		})
 		if !found {
-			errs = append(errs, fmt.Errorf("unused interface binding to type %s", types.TypeString(b.Iface, nil)))
+			errs = append(errs, fmt.Errorf("unused interface binding to type %s", typeString(b.Iface, nil)))
 		}
 	}
+	wildcards := make(map[token.Pos]bool)
 	for _, f := range set.Fields {
-		found := false
-		for _, u := range used {
-			if u.Field == f {
-				found = true
-				break
+		if f.Wildcard.IsValid() {
+			if wildcards[f.Wildcard] {
+				continue
+			}
+			wildcards[f.Wildcard] = true
+			found := usedBy(func(_ types.Type, pt ProvidedType) bool {
+				return pt.f != nil && pt.f.Wildcard == f.Wildcard
+			})
+			if !found {
+				errs = append(errs, fmt.Errorf("unused autowire.FieldsOf of %s", typeString(f.Parent, nil)))
 			}
+			continue
 		}
+		found := usedBy(func(_ types.Type, pt ProvidedType) bool {
+			return pt.f == f
+		})
 		if !found {
 			errs = append(errs, fmt.Errorf("unused field %q.%s", f.Parent, f.Name))
 		}
@@ -326,6 +931,364 @@ func verifyArgsUsed(set *ProviderSet, used []*providerSetSrc) []error {
 	return errs
 }
 
+// setUsage records which members of the provider sets included by injectors
+// were used by at least one of those injectors. Sets and their members are
+// identified by their positions, since each package loads its own copy of a
+// provider set.
+type setUsage struct {
+	sets  map[string]*setMembers
+	order []*setMembers
+}
+
+// setMembers is the usage of the members of a provider set.
+type setMembers struct {
+	pkgPath string
+	varName string
+	pos     token.Position
+	members map[string]*setMember
+	order   []*setMember
+}
+
+// setMember is a provider, value, interface binding or field declared in a
+// provider set.
+type setMember struct {
+	desc string
+	pos  token.Position
+	used bool
+}
+
+func newSetUsage() *setUsage {
+	return &setUsage{sets: make(map[string]*setMembers)}
+}
+
+// record marks the members of the sets included by top that were used to
+// solve an injector for it. used holds the types that solve looked up in top.
+// The members of top itself are not recorded, since verifyArgsUsed already
+// requires all of them to be used.
+func (u *setUsage) record(fset *token.FileSet, top *ProviderSet, used *typeutil.Map) {
+	visited := make(map[*ProviderSet]bool)
+	var visit func(set *ProviderSet)
+	visit = func(set *ProviderSet) {
+		for _, imp := range set.Imports {
+			if visited[imp] {
+				continue
+			}
+			visited[imp] = true
+			u.recordSet(fset, imp, top, used)
+			visit(imp)
+		}
+	}
+	visit(top)
+}
+
+func (u *setUsage) recordSet(fset *token.FileSet, set, top *ProviderSet, used *typeutil.Map) {
+	pos := fset.Position(set.Pos)
+	sm := u.sets[pos.String()]
+	if sm == nil {
+		sm = &setMembers{
+			pkgPath: set.PkgPath,
+			varName: set.VarName,
+			pos:     pos,
+			members: make(map[string]*setMember),
+		}
+		u.sets[pos.String()] = sm
+		u.order = append(u.order, sm)
+	}
+	mark := func(desc string, pos token.Pos, match func(t types.Type, pt ProvidedType) bool) {
+		key := desc + "@" + fset.Position(pos).String()
+		m := sm.members[key]
+		if m == nil {
+			m = &setMember{desc: desc, pos: fset.Position(pos)}
+			sm.members[key] = m
+			sm.order = append(sm.order, m)
+		}
+		m.used = m.used || usedBy(top, used, match)
+	}
+	for _, p := range set.Providers {
+		p := p
+		desc := fmt.Sprintf("provider %q", p.Pkg.Name()+"."+p.Name)
+		if p.IsCollect || p.IsOptions {
+			desc = fmt.Sprintf("autowire.%s of type %s", p.Name, typeString(p.Out[0], nil))
+		}
+		mark(desc, p.Pos, func(_ types.Type, pt ProvidedType) bool {
+			return pt.p != nil && sameProvider(pt.p, p)
+		})
+	}
+	for _, v := range set.Values {
+		v := v
+		mark(fmt.Sprintf("value of type %s", typeString(v.Out, nil)), v.Pos, func(_ types.Type, pt ProvidedType) bool {
+			return pt.v == v
+		})
+	}
+	for _, b := range set.Bindings {
+		b := b
+		mark(fmt.Sprintf("interface binding to type %s", typeString(b.Iface, nil)), b.Pos, func(t types.Type, _ ProvidedType) bool {
+			return types.Identical(t, b.Iface)
+		})
+	}
+	for _, f := range set.Fields {
+		f := f
+		if f.Wildcard.IsValid() {
+			mark(fmt.Sprintf("autowire.FieldsOf of %s", typeString(f.Parent, nil)), f.Wildcard, func(_ types.Type, pt ProvidedType) bool {
+				return pt.f != nil && pt.f.Wildcard == f.Wildcard
+			})
+			continue
+		}
+		mark(fmt.Sprintf("field %q.%s", f.Parent, f.Name), f.Pos, func(_ types.Type, pt ProvidedType) bool {
+			return pt.f == f
+		})
+	}
+}
+
+// snapshot returns the recorded usage in a form that can be stored in the
+// generation cache.
+func (u *setUsage) snapshot() []cachedSet {
+	sets := make([]cachedSet, 0, len(u.order))
+	for _, sm := range u.order {
+		cs := cachedSet{PkgPath: sm.pkgPath, VarName: sm.varName, Pos: sm.pos}
+		for _, m := range sm.order {
+			cs.Members = append(cs.Members, cachedMember{Desc: m.desc, Pos: m.pos, Used: m.used})
+		}
+		sets = append(sets, cs)
+	}
+	return sets
+}
+
+// merge adds the usage recorded by other to u.
+func (u *setUsage) merge(other *setUsage) {
+	for _, sm := range other.order {
+		for _, m := range sm.order {
+			u.add(sm, m)
+		}
+	}
+}
+
+// mergeSnapshot adds usage stored by snapshot to u.
+func (u *setUsage) mergeSnapshot(sets []cachedSet) {
+	for _, cs := range sets {
+		sm := &setMembers{pkgPath: cs.PkgPath, varName: cs.VarName, pos: cs.Pos}
+		for _, cm := range cs.Members {
+			u.add(sm, &setMember{desc: cm.Desc, pos: cm.Pos, used: cm.Used})
+		}
+	}
+}
+
+// add records m as a member of the set described by set.
+func (u *setUsage) add(set *setMembers, m *setMember) {
+	key := set.pos.String()
+	sm := u.sets[key]
+	if sm == nil {
+		sm = &setMembers{
+			pkgPath: set.pkgPath,
+			varName: set.varName,
+			pos:     set.pos,
+			members: make(map[string]*setMember),
+		}
+		u.sets[key] = sm
+		u.order = append(u.order, sm)
+	}
+	mkey := m.desc + "@" + m.pos.String()
+	prev := sm.members[mkey]
+	if prev == nil {
+		prev = &setMember{desc: m.desc, pos: m.pos}
+		sm.members[mkey] = prev
+		sm.order = append(sm.order, prev)
+	}
+	prev.used = prev.used || m.used
+}
+
+// unused returns a warning for each member of a set declared in a package for
+// which include returns true that no injector used.
+func (u *setUsage) unused(include func(pkgPath string) bool) map[string][]error {
+	warnings := make(map[string][]error)
+	for _, sm := range u.order {
+		if !include(sm.pkgPath) {
+			continue
+		}
+		setDesc := "provider set"
+		if sm.varName != "" {
+			setDesc = fmt.Sprintf("provider set %q", sm.varName)
+		}
+		for _, m := range sm.order {
+			if m.used {
+				continue
+			}
+			warnings[sm.pkgPath] = append(warnings[sm.pkgPath], notePosition(m.pos,
+				fmt.Errorf("%s in %s (%s) is not used by any injector", m.desc, setDesc, sm.pos)))
+		}
+	}
+	return warnings
+}
+
+// pointerHint explains why t is not provided if set provides a pointer to t,
+// or t is a pointer and set provides the type it points to. It returns the
+// empty string otherwise.
+func pointerHint(fset *token.FileSet, set *ProviderSet, t types.Type) string {
+	other := types.Type(types.NewPointer(t))
+	what := "dereference pointers"
+	if ptr, ok := t.(*types.Pointer); ok {
+		other = ptr.Elem()
+		what = "take the address of values"
+	}
+	src, ok := set.srcMap.At(other).(*providerSetSrc)
+	if !ok {
+		return ""
+	}
+	hint := fmt.Sprintf("; %s is provided by %s, but Autowire does not %s", typeString(other, nil), src.description(fset, other), what)
+	if isStructType(t) {
+		hint += "; use autowire.Struct to provide both"
+	}
+	return hint
+}
+
+// lazyHint suggests autowire.Lazy if t is a factory type, a function without
+// parameters, whose first result is provided by a provider function in set.
+// It returns the empty string otherwise.
+func lazyHint(fset *token.FileSet, set *ProviderSet, t types.Type) string {
+	sig, ok := t.(*types.Signature)
+	if !ok || sig.Params().Len() > 0 || sig.Variadic() || sig.Results().Len() == 0 {
+		return ""
+	}
+	res := sig.Results().At(0).Type()
+	p, ok := set.providerMap.At(res).(*ProvidedType)
+	if !ok || !p.IsProvider() || p.Provider().IsStruct || p.Provider().IsCollect || p.Provider().IsOptions {
+		return ""
+	}
+	return fmt.Sprintf("; %s is provided by %s; pass the provider to autowire.Lazy to provide a factory for it", typeString(res, nil), set.srcMap.At(res).(*providerSetSrc).description(fset, res))
+}
+
+// lazyFactoryResult reports whether t has the shape of a factory provided by
+// autowire.Lazy, a function without parameters that returns a value,
+// optionally followed by a cleanup function and an error. If so, it returns
+// the type of the value.
+func lazyFactoryResult(t types.Type) (types.Type, bool) {
+	sig, ok := t.(*types.Signature)
+	if !ok || sig.Params().Len() > 0 || sig.Variadic() {
+		return nil, false
+	}
+	results := sig.Results()
+	if results.Len() == 0 || results.Len() > 3 {
+		return nil, false
+	}
+	rest := []types.Type{cleanupType, errorType}
+	if results.Len() == 2 && types.Identical(results.At(1).Type(), errorType) {
+		rest = rest[1:]
+	}
+	for i := 1; i < results.Len(); i++ {
+		if !types.Identical(results.At(i).Type(), rest[i-1]) {
+			return nil, false
+		}
+	}
+	return results.At(0).Type(), true
+}
+
+// similarTypes returns the named types provided by set, other than the
+// pointer or value counterpart of t reported by pointerHint, whose names are
+// close to the name of t: the same apart from case or package, or a few edits
+// apart. These are likely typos or a type from the wrong package.
+func similarTypes(set *ProviderSet, t types.Type) []types.Type {
+	name := typeBaseName(t)
+	if name == "" {
+		return nil
+	}
+	var similar []types.Type
+	set.providerMap.Iterate(func(pt types.Type, _ interface{}) {
+		if ptr, ok := t.(*types.Pointer); ok && types.Identical(pt, ptr.Elem()) || types.Identical(pt, types.NewPointer(t)) {
+			return
+		}
+		other := typeBaseName(pt)
+		if other == "" || types.Identical(pt, t) {
+			return
+		}
+		if strings.EqualFold(name, other) || editDistance(strings.ToLower(name), strings.ToLower(other)) <= len(name)/4 {
+			similar = append(similar, pt)
+		}
+	})
+	sort.Slice(similar, func(i, j int) bool {
+		return typeString(similar[i], nil) < typeString(similar[j], nil)
+	})
+	if len(similar) > 3 {
+		similar = similar[:3]
+	}
+	return similar
+}
+
+// typeBaseName returns the name of t, or of the type t points to, if it is a
+// named type declared in a package.
+func typeBaseName(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	return named.Obj().Name()
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if d := prev[j] + 1; d < curr[j] {
+				curr[j] = d
+			}
+			if d := curr[j-1] + 1; d < curr[j] {
+				curr[j] = d
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// isStructType reports whether t is a named struct type or a pointer to one.
+func isStructType(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		// Qualified types are structs without a package.
+		return false
+	}
+	_, ok = named.Underlying().(*types.Struct)
+	return ok
+}
+
+// usedBy reports whether match is true for any of the used types, together
+// with what set provides for it.
+func usedBy(set *ProviderSet, used *typeutil.Map, match func(t types.Type, pt ProvidedType) bool) bool {
+	found := false
+	used.Iterate(func(t types.Type, _ interface{}) {
+		if !found && match(t, set.For(t)) {
+			found = true
+		}
+	})
+	return found
+}
+
+// withHasher returns a copy of m that uses hasher.
+func withHasher(m *typeutil.Map, hasher typeutil.Hasher) *typeutil.Map {
+	c := new(typeutil.Map)
+	c.SetHasher(hasher)
+	m.Iterate(func(k types.Type, v interface{}) {
+		c.Set(k, v)
+	})
+	return c
+}
+
 // buildProviderMap creates the providerMap and srcMap fields for a given
 // provider set. The given provider set's providerMap and srcMap fields are
 // ignored.
@@ -356,6 +1319,16 @@ func buildProviderMap(fset *token.FileSet, hasher typeutil.Hasher, set *Provider
 		src := &providerSetSrc{Import: imp}
 		imp.providerMap.Iterate(func(k types.Type, v interface{}) {
 			if prevSrc := srcMap.At(k); prevSrc != nil {
+				if isDuplicate(providerMap.At(k).(*ProvidedType), v.(*ProvidedType)) {
+					return
+				}
+				if resolved, replace := testOverride(fset, set, k, src, prevSrc.(*providerSetSrc)); resolved {
+					if replace {
+						providerMap.Set(k, v)
+						srcMap.Set(k, src)
+					}
+					return
+				}
 				ec.add(bindingConflictError(fset, k, set, src, prevSrc.(*providerSetSrc)))
 				return
 			}
@@ -371,17 +1344,35 @@ func buildProviderMap(fset *token.FileSet, hasher typeutil.Hasher, set *Provider
 	for _, p := range set.Providers {
 		src := &providerSetSrc{Provider: p}
 		for _, typ := range p.Out {
+			pt := &ProvidedType{t: typ, p: p}
 			if prevSrc := srcMap.At(typ); prevSrc != nil {
+				if isDuplicate(providerMap.At(typ).(*ProvidedType), pt) {
+					continue
+				}
+				if resolved, replace := testOverride(fset, set, typ, src, prevSrc.(*providerSetSrc)); resolved {
+					if replace {
+						providerMap.Set(typ, pt)
+						srcMap.Set(typ, src)
+					}
+					continue
+				}
 				ec.add(bindingConflictError(fset, typ, set, src, prevSrc.(*providerSetSrc)))
 				continue
 			}
-			providerMap.Set(typ, &ProvidedType{t: typ, p: p})
+			providerMap.Set(typ, pt)
 			srcMap.Set(typ, src)
 		}
 	}
 	for _, v := range set.Values {
 		src := &providerSetSrc{Value: v}
 		if prevSrc := srcMap.At(v.Out); prevSrc != nil {
+			if resolved, replace := testOverride(fset, set, v.Out, src, prevSrc.(*providerSetSrc)); resolved {
+				if replace {
+					providerMap.Set(v.Out, &ProvidedType{t: v.Out, v: v})
+					srcMap.Set(v.Out, src)
+				}
+				continue
+			}
 			ec.add(bindingConflictError(fset, v.Out, set, src, prevSrc.(*providerSetSrc)))
 			continue
 		}
@@ -391,11 +1382,22 @@ func buildProviderMap(fset *token.FileSet, hasher typeutil.Hasher, set *Provider
 	for _, f := range set.Fields {
 		src := &providerSetSrc{Field: f}
 		for _, typ := range f.Out {
+			pt := &ProvidedType{t: typ, f: f}
 			if prevSrc := srcMap.At(typ); prevSrc != nil {
+				if isDuplicate(providerMap.At(typ).(*ProvidedType), pt) {
+					continue
+				}
+				if resolved, replace := testOverride(fset, set, typ, src, prevSrc.(*providerSetSrc)); resolved {
+					if replace {
+						providerMap.Set(typ, pt)
+						srcMap.Set(typ, src)
+					}
+					continue
+				}
 				ec.add(bindingConflictError(fset, typ, set, src, prevSrc.(*providerSetSrc)))
 				continue
 			}
-			providerMap.Set(typ, &ProvidedType{t: typ, f: f})
+			providerMap.Set(typ, pt)
 			srcMap.Set(typ, src)
 		}
 	}
@@ -407,11 +1409,23 @@ func buildProviderMap(fset *token.FileSet, hasher typeutil.Hasher, set *Provider
 	// ensure the concrete type is being provided.
 	for _, b := range set.Bindings {
 		src := &providerSetSrc{Binding: b}
+		concrete := providerMap.At(b.Provided)
 		if prevSrc := srcMap.At(b.Iface); prevSrc != nil {
+			if concrete != nil && isDuplicate(providerMap.At(b.Iface).(*ProvidedType), concrete.(*ProvidedType)) {
+				continue
+			}
+			if concrete != nil {
+				if resolved, replace := testOverride(fset, set, b.Iface, src, prevSrc.(*providerSetSrc)); resolved {
+					if replace {
+						providerMap.Set(b.Iface, concrete)
+						srcMap.Set(b.Iface, src)
+					}
+					continue
+				}
+			}
 			ec.add(bindingConflictError(fset, b.Iface, set, src, prevSrc.(*providerSetSrc)))
 			continue
 		}
-		concrete := providerMap.At(b.Provided)
 		if concrete == nil {
 			setName := set.VarName
 			if setName == "" {
@@ -429,7 +1443,81 @@ func buildProviderMap(fset *token.FileSet, hasher typeutil.Hasher, set *Provider
 	return providerMap, srcMap, nil
 }
 
-func verifyAcyclic(providerMap *typeutil.Map, hasher typeutil.Hasher) []error {
+// buildDefaultMap creates the defaultMap field for a given provider set,
+// combining its default providers with those of the sets it imports. A set
+// may have at most one default provider for each interface type.
+func buildDefaultMap(fset *token.FileSet, hasher typeutil.Hasher, set *ProviderSet) (*typeutil.Map, []error) {
+	defaultMap := new(typeutil.Map) // to *DefaultProvider
+	defaultMap.SetHasher(hasher)
+	ec := new(errorCollector)
+	add := func(d *DefaultProvider) {
+		prev, _ := defaultMap.At(d.Iface).(*DefaultProvider)
+		switch {
+		case prev == nil:
+			defaultMap.Set(d.Iface, d)
+		case prev != d && !sameProvider(prev.Provider, d.Provider):
+			ec.add(notePosition(fset.Position(set.Pos), fmt.Errorf("multiple default providers for %s: %s (%s) and %s (%s)", typeString(d.Iface, nil), d.Provider.Pkg.Name()+"."+d.Provider.Name, fset.Position(d.Pos), prev.Provider.Pkg.Name()+"."+prev.Provider.Name, fset.Position(prev.Pos))))
+		}
+	}
+	for _, imp := range set.Imports {
+		if imp.defaultMap == nil {
+			continue
+		}
+		imp.defaultMap.Iterate(func(_ types.Type, v interface{}) {
+			add(v.(*DefaultProvider))
+		})
+	}
+	for _, d := range set.Defaults {
+		add(d)
+	}
+	if len(ec.errors) > 0 {
+		return nil, ec.errors
+	}
+	return defaultMap, nil
+}
+
+// testOverride resolves a conflict between the sources src and prev of typ in
+// set if set is declared in a _test.go file, and so can only be used by test
+// injectors, and exactly one of src and prev is also declared in a _test.go
+// file. This lets test injectors replace providers from the sets they reuse.
+// It reports whether the conflict is resolved, and if so, whether src replaces
+// prev. Each replacement is recorded as a warning in set.overrides.
+func testOverride(fset *token.FileSet, set *ProviderSet, typ types.Type, src, prev *providerSetSrc) (resolved, replace bool) {
+	if !isTestPos(fset, set.Pos) {
+		return false, false
+	}
+	srcTest, prevTest := isTestPos(fset, src.pos()), isTestPos(fset, prev.pos())
+	if srcTest == prevTest {
+		return false, false
+	}
+	override, replaced := src, prev
+	if prevTest {
+		override, replaced = prev, src
+	}
+	set.overrides = append(set.overrides, notePosition(fset.Position(override.pos()), fmt.Errorf("%s replaces %s for %s", override.description(fset, typ), replaced.description(fset, typ), typeString(typ, nil))))
+	return true, srcTest
+}
+
+// isTestPos reports whether pos is in a _test.go file.
+func isTestPos(fset *token.FileSet, pos token.Pos) bool {
+	return strings.HasSuffix(fset.Position(pos).Filename, "_test.go")
+}
+
+// overrides returns the replacements recorded by testOverride in set and the
+// sets it imports, skipping the sets in seen and adding the others to it.
+func overrides(set *ProviderSet, seen map[*ProviderSet]bool) []error {
+	if seen[set] {
+		return nil
+	}
+	seen[set] = true
+	var errs []error
+	for _, imp := range set.Imports {
+		errs = append(errs, overrides(imp, seen)...)
+	}
+	return append(errs, set.overrides...)
+}
+
+func verifyAcyclic(fset *token.FileSet, providerMap *typeutil.Map, hasher typeutil.Hasher) []error {
 	// We must visit every provider type inside provider map, but we don't
 	// have a well-defined starting point and there may be several
 	// distinct graphs. Thus, we start a depth-first search at every
@@ -440,7 +1528,7 @@ func verifyAcyclic(providerMap *typeutil.Map, hasher typeutil.Hasher) []error {
 	ec := new(errorCollector)
 	// Sort output types so that errors about cycles are consistent.
 	outputs := providerMap.Keys()
-	sort.Slice(outputs, func(i, j int) bool { return types.TypeString(outputs[i], nil) < types.TypeString(outputs[j], nil) })
+	sort.Slice(outputs, func(i, j int) bool { return typeString(outputs[i], nil) < typeString(outputs[j], nil) })
 	for _, root := range outputs {
 		// Depth-first search using a stack of trails through the provider map.
 		stk := [][]types.Type{{root}}
@@ -467,7 +1555,12 @@ func verifyAcyclic(providerMap *typeutil.Map, hasher typeutil.Hasher) []error {
 				var args []types.Type
 				if pt.IsProvider() {
 					for _, arg := range pt.Provider().Args {
-						args = append(args, arg.Type)
+						t, err := inputType(providerMap, arg)
+						if err != nil {
+							// Reported by solve.
+							t = arg.Type
+						}
+						args = append(args, t)
 					}
 				} else {
 					args = append(args, pt.Field().Parent)
@@ -476,20 +1569,10 @@ func verifyAcyclic(providerMap *typeutil.Map, hasher typeutil.Hasher) []error {
 					hasCycle := false
 					for i, b := range curr {
 						if types.Identical(a, b) {
-							sb := new(strings.Builder)
-							fmt.Fprintf(sb, "cycle for %s:\n", types.TypeString(a, nil))
-							for j := i; j < len(curr); j++ {
-								t := providerMap.At(curr[j]).(*ProvidedType)
-								if t.IsProvider() {
-									p := t.Provider()
-									fmt.Fprintf(sb, "%s (%s.%s) ->\n", types.TypeString(curr[j], nil), p.Pkg.Path(), p.Name)
-								} else {
-									p := t.Field()
-									fmt.Fprintf(sb, "%s (%s.%s) ->\n", types.TypeString(curr[j], nil), p.Parent, p.Name)
-								}
-							}
-							fmt.Fprintf(sb, "%s", types.TypeString(a, nil))
-							ec.add(errors.New(sb.String()))
+							path := append(append([]types.Type(nil), curr[i:]...), a)
+							ec.add(cycleError(fset, path, func(t types.Type) ProvidedType {
+								return *providerMap.At(t).(*ProvidedType)
+							}))
 							hasCycle = true
 							break
 						}
@@ -507,6 +1590,143 @@ func verifyAcyclic(providerMap *typeutil.Map, hasher typeutil.Hasher) []error {
 	return ec.errors
 }
 
+// cycleError returns an error describing a dependency cycle. path lists the
+// types in the cycle, each one needed by the provider of the one before it,
+// and ends with the type it starts with. provided returns how each type in
+// path is provided.
+//
+// An interface in path may be followed by the concrete type bound to it, or
+// directly by a type its provider needs, depending on whether the binding was
+// implicit. The concrete type is added in the latter case, so that the cycle
+// reads the same either way.
+func cycleError(fset *token.FileSet, path []types.Type, provided func(types.Type) ProvidedType) error {
+	full := make([]types.Type, 0, len(path))
+	for i, t := range path {
+		full = append(full, t)
+		if i == len(path)-1 {
+			break
+		}
+		if bound := provided(t).Type(); !types.Identical(bound, t) && !types.Identical(bound, path[i+1]) {
+			full = append(full, bound)
+		}
+	}
+	path = full
+	names := make([]string, len(path))
+	for i, t := range path {
+		names[i] = typeString(t, nil)
+	}
+	sb := new(strings.Builder)
+	fmt.Fprintf(sb, "cycle for %s: %s", names[0], strings.Join(names, " -> "))
+	for i, t := range path[:len(path)-1] {
+		if i > 0 && types.Identical(provided(path[i-1]).Type(), t) {
+			// Already described by the interface bound to t.
+			continue
+		}
+		pt := provided(t)
+		fmt.Fprintf(sb, "\n%s", names[i])
+		if !types.Identical(pt.Type(), t) {
+			fmt.Fprintf(sb, " (bound to %s)", typeString(pt.Type(), nil))
+		}
+		switch {
+		case pt.IsProvider() && (pt.Provider().IsCollect || pt.Provider().IsOptions):
+			fmt.Fprintf(sb, " is provided by autowire.%s (%s)", pt.Provider().Name, fset.Position(pt.Provider().Pos))
+		case pt.IsProvider():
+			p := pt.Provider()
+			kind := "provider"
+			if p.IsStruct {
+				kind = "struct provider"
+			}
+			fmt.Fprintf(sb, " is provided by %s %s.%s (%s)", kind, p.Pkg.Path(), p.Name, fset.Position(p.Pos))
+		case pt.IsField():
+			f := pt.Field()
+			fmt.Fprintf(sb, " is provided by field %s of %s (%s)", f.Name, typeString(f.Parent, nil), fset.Position(f.Pos))
+		}
+	}
+	// A provider that needs an interface can receive it from a setter after
+	// it is built instead, which breaks the cycle.
+	for i := 1; i < len(path)-1; i++ {
+		if !types.IsInterface(path[i]) || types.Identical(provided(path[i-1]).Type(), path[i]) {
+			continue
+		}
+		if pt := provided(path[i-1]); pt.IsProvider() && !pt.Provider().IsStruct && !pt.Provider().IsCollect && !pt.Provider().IsOptions {
+			fmt.Fprintf(sb, "\nto break the cycle, remove the %s parameter from %s and set it after construction with autowire.LateBind", names[i], pt.Provider().Name)
+			break
+		}
+	}
+	return errors.New(sb.String())
+}
+
+// isDuplicate reports whether cur provides a type in the same way as prev.
+// This happens when the same provider is reachable through more than one
+// provider set, and is not considered a conflict.
+//
+// Function providers, fields and values are duplicates only if they come from
+// the same declaration or call. Struct providers are also duplicates if they
+// fill in the same fields of the same struct type, and instances of a generic
+// function if they have the same type arguments. Injector arguments are never
+// duplicates.
+func isDuplicate(prev, cur *ProvidedType) bool {
+	if !types.Identical(prev.t, cur.t) {
+		return false
+	}
+	if prev.p != nil && cur.p != nil {
+		return sameProvider(prev.p, cur.p)
+	}
+	return prev.sameSource(*cur) && prev.a == nil
+}
+
+// sameProvider reports whether p and q provide their types in the same way:
+// they are the same provider, struct providers that fill in the same fields of
+// the same type, instantiations of the same generic function with the same
+// type arguments, method expressions for the same method, or calls to
+// autowire.Collect for the same slice type.
+func sameProvider(p, q *Provider) bool {
+	if p.IsLazy != q.IsLazy {
+		return false
+	}
+	if p.IsCollect && q.IsCollect {
+		return types.Identical(p.Out[0], q.Out[0])
+	}
+	return p == q || sameStructProvider(p, q) || sameFuncInstance(p, q) || sameMethod(p, q)
+}
+
+// sameMethod reports whether p and q are method expressions for the same
+// method with the same receiver type.
+func sameMethod(p, q *Provider) bool {
+	if !p.IsMethod || !q.IsMethod || p.Pkg != q.Pkg || p.Name != q.Name || p.Qualifier != q.Qualifier {
+		return false
+	}
+	return types.Identical(p.Args[0].Type, q.Args[0].Type)
+}
+
+// sameFuncInstance reports whether p and q are instantiations of the same
+// generic function with identical type arguments.
+func sameFuncInstance(p, q *Provider) bool {
+	if p.IsStruct || q.IsStruct || p.Pkg != q.Pkg || p.Name != q.Name || p.Qualifier != q.Qualifier || len(p.TypeArgs) == 0 || len(p.TypeArgs) != len(q.TypeArgs) {
+		return false
+	}
+	for i := range p.TypeArgs {
+		if !types.Identical(p.TypeArgs[i], q.TypeArgs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameStructProvider reports whether p and q are struct providers for the same
+// type that fill in the same fields.
+func sameStructProvider(p, q *Provider) bool {
+	if !p.IsStruct || !q.IsStruct || p.Pkg != q.Pkg || p.Name != q.Name || len(p.Args) != len(q.Args) {
+		return false
+	}
+	for i := range p.Args {
+		if p.Args[i].FieldName != q.Args[i].FieldName {
+			return false
+		}
+	}
+	return true
+}
+
 // bindingConflictError creates a new error describing multiple bindings
 // for the same output type.
 func bindingConflictError(fset *token.FileSet, typ types.Type, set *ProviderSet, cur, prev *providerSetSrc) error {
@@ -514,8 +1734,23 @@ func bindingConflictError(fset *token.FileSet, typ types.Type, set *ProviderSet,
 	if set.VarName != "" {
 		fmt.Fprintf(sb, "%s has ", set.VarName)
 	}
-	fmt.Fprintf(sb, "multiple bindings for %s\n", types.TypeString(typ, nil))
+	fmt.Fprintf(sb, "multiple bindings for %s\n", typeString(typ, nil))
 	fmt.Fprintf(sb, "current:\n<- %s\n", strings.Join(cur.trace(fset, typ), "\n<- "))
 	fmt.Fprintf(sb, "previous:\n<- %s", strings.Join(prev.trace(fset, typ), "\n<- "))
+	if q, _, ok := qualifier(typ); ok && token.IsIdentifier(q) && cur.provider(typ) != nil && prev.provider(typ) != nil {
+		fmt.Fprintf(sb, "\nboth are qualified as %q; if both are needed, give them distinct names with autowire.Named", q)
+	} else if cur.provider(typ) != nil && prev.provider(typ) != nil {
+		sb.WriteString("\nif both are needed, qualify them with autowire.Named")
+	} else if cf, pf := cur.field(typ), prev.field(typ); cf != nil && pf != nil && !types.Identical(cf.Parent, pf.Parent) {
+		sb.WriteString("\nif both are needed, give the fields distinct types, or list only one of them in autowire.FieldsOf and provide the other with a provider function qualified by autowire.Named")
+	}
 	return notePosition(fset.Position(set.Pos), errors.New(sb.String()))
 }
+
+// isZeroFillable reports whether t is a boolean, numeric or string type that
+// is not a named type, which GenerateOptions.ZeroFillBasics fills in with its
+// zero value.
+func isZeroFillable(t types.Type) bool {
+	b, ok := t.(*types.Basic)
+	return ok && b.Info()&(types.IsBoolean|types.IsNumeric|types.IsString) != 0 && b.Info()&types.IsUntyped == 0
+}
@@ -28,14 +28,17 @@ import (
 	"go/types"
 	"io/ioutil"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 	"unicode/utf8"
 
 	"golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
 )
 
 // GenerateResult stores the result for a package from a call to Generate.
@@ -50,6 +53,19 @@ type GenerateResult struct {
 	Content []byte
 	// Errs is a slice of errors identified during generation.
 	Errs []error
+	// Warnings is a slice of problems identified during generation that do
+	// not prevent it, such as members of the package's provider sets that no
+	// injector uses.
+	Warnings []error
+	// Unchanged reports that the package was skipped because neither it nor
+	// its dependencies changed since the file at OutputPath was generated.
+	// Content is nil, since the file is already up to date.
+	Unchanged bool
+
+	// destSum is the sum of the destination package's files when the
+	// injectors were generated into it with autowire.Into, since the names
+	// they declare change the generated code.
+	destSum string
 }
 
 // Commit writes the generated file to disk.
@@ -63,10 +79,114 @@ func (gen GenerateResult) Commit() error {
 // GenerateOptions holds options for Generate.
 type GenerateOptions struct {
 	// Header will be inserted at the start of each generated file.
-	Header               []byte
-	PrefixOutputFile     string
-	Tags                 string
+	Header           []byte
+	PrefixOutputFile string
+	// OutputFile is the name of the generated file, which is written to the
+	// package's directory. It defaults to autowire_gen.go. Injectors declared
+	// in _test.go files are written to the same name with a _test.go suffix.
+	OutputFile             string
+	Tags                   string
 	NoAddGenerateDirective bool
+	// Graphs, if not nil, receives the resolved dependency graph of each
+	// injector. It does not affect the generated files.
+	Graphs GraphWriter
+	// Strict reports the members of provider sets that no injector uses as
+	// errors instead of warnings.
+	Strict bool
+	// Annotate adds a comment to the start of each generated injector that
+	// lists the values it builds in order, along with what builds each of them
+	// and where that is declared. It does not change the generated code.
+	Annotate bool
+	// ZeroFillBasics passes the zero value for each input of a bool, numeric
+	// or string type that nothing provides, instead of reporting an error.
+	// Named types, such as time.Duration, are never zero filled. This is
+	// meant for prototyping, since it hides missing configuration.
+	ZeroFillBasics bool
+	// GOOS and GOARCH, if not empty, are the operating system and the
+	// architecture to load the packages for, overriding those in the
+	// environment passed to Generate, so that only the files whose build
+	// constraints match them are used. They are also added to the names of the
+	// generated files as suffixes, such as autowire_gen_linux_arm64.go, so that
+	// each generated file is only built for the platform it was generated for.
+	GOOS   string
+	GOARCH string
+	// MaxFieldDepth, if positive, limits how deeply the fields named by
+	// autowire.FieldsOf may be nested: 1 only allows fields declared in the
+	// struct itself, 2 also allows fields promoted from its embedded structs,
+	// and so on. Zero means no limit.
+	MaxFieldDepth int
+	// CacheDir, if not empty, is a directory in which Generate records the
+	// inputs of each package it generates. A package whose source files and
+	// transitive dependencies, the options and the autowire binary are all
+	// unchanged since then, and whose generated files have not been modified,
+	// is skipped. The cache is not used when Graphs is set.
+	CacheDir string
+}
+
+// outputFiles returns the names of the files that the injectors of a package
+// and of its tests are written to.
+func (opts *GenerateOptions) outputFiles() (name, testName string, _ error) {
+	name = opts.OutputFile
+	if name == "" {
+		name = "autowire_gen.go"
+	}
+	if filepath.Base(name) != name {
+		return "", "", fmt.Errorf("output file %q must be a file name in the package's directory", name)
+	}
+	if filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+		return "", "", fmt.Errorf("output file %q must have a .go suffix and must not be a _test.go file", name)
+	}
+	name = opts.PrefixOutputFile + strings.TrimSuffix(name, ".go")
+	for _, suffix := range []string{opts.GOOS, opts.GOARCH} {
+		if suffix != "" {
+			name += "_" + suffix
+		}
+	}
+	return name + ".go", name + "_test.go", nil
+}
+
+// platformEnv returns env with the GOOS and GOARCH of opts, if they are set.
+func (opts *GenerateOptions) platformEnv(env []string) []string {
+	env = env[:len(env):len(env)]
+	if opts.GOOS != "" {
+		env = append(env, "GOOS="+opts.GOOS)
+	}
+	if opts.GOARCH != "" {
+		env = append(env, "GOARCH="+opts.GOARCH)
+	}
+	return env
+}
+
+// generateDirective returns the arguments to the autowire command that
+// regenerate a file generated with opts.
+func (opts *GenerateOptions) generateDirective() string {
+	var args []string
+	if opts.PrefixOutputFile != "" {
+		args = append(args, fmt.Sprintf("-output-file-prefix %q", opts.PrefixOutputFile))
+	}
+	if opts.OutputFile != "" {
+		args = append(args, fmt.Sprintf("-output %q", opts.OutputFile))
+	}
+	if opts.Tags != "" {
+		args = append(args, fmt.Sprintf("-tags %q", opts.Tags))
+	}
+	if opts.GOOS != "" {
+		args = append(args, fmt.Sprintf("-os %q", opts.GOOS))
+	}
+	if opts.GOARCH != "" {
+		args = append(args, fmt.Sprintf("-arch %q", opts.GOARCH))
+	}
+	if opts.ZeroFillBasics {
+		// The injectors would fail to generate without it.
+		args = append(args, "-zero-fill-basics")
+	}
+	if opts.Annotate {
+		args = append(args, "-annotate")
+	}
+	if len(args) == 0 {
+		return ""
+	}
+	return " gen " + strings.Join(args, " ")
 }
 
 // Generate performs dependency injection for the packages that match the given
@@ -74,6 +194,21 @@ type GenerateOptions struct {
 // defined by the underlying build system. For the go tool, this is described at
 // https://golang.org/cmd/go/#hdr-Package_lists_and_patterns
 //
+// Injectors declared in a package's _test.go files are generated separately,
+// into an autowire_gen_test.go file, so that they can only be used by the
+// package's tests. Such a package has a second GenerateResult for that file.
+// Injectors that use autowire.Into have a GenerateResult for the package they
+// are generated into.
+//
+// Generate does not write the generated files: each GenerateResult holds the
+// formatted source and its suggested path, and the caller decides whether to
+// write it with Commit. Only the cache in GenerateOptions.CacheDir is written
+// to. Tools outside this module use Generate through the
+// github.com/dabbertorres/autowire/gen package.
+//
+// The injectors of a package are solved concurrently, but the generated code
+// and any errors are in the order the injectors are declared.
+//
 // wd is the working directory and env is the set of environment
 // variables to use when loading the package specified by pkgPattern. If
 // env is nil or empty, it is interpreted as an empty set of variables.
@@ -85,43 +220,230 @@ func Generate(ctx context.Context, wd string, env []string, patterns []string, o
 	if opts == nil {
 		opts = &GenerateOptions{}
 	}
-	pkgs, errs := load(ctx, wd, env, opts.Tags, patterns)
-	if len(errs) > 0 {
-		return nil, errs
+	outputFile, testOutputFile, err := opts.outputFiles()
+	if err != nil {
+		return nil, []error{err}
+	}
+	env = opts.platformEnv(env)
+	var cache *genCache
+	if opts.CacheDir != "" && opts.Graphs == nil {
+		cache = openCache(ctx, opts.CacheDir, wd, env, patterns, opts)
+	}
+	loadPatterns := patterns
+	if cache != nil {
+		loadPatterns = cache.misses()
+	}
+	var pkgs []*packages.Package
+	if len(loadPatterns) > 0 {
+		var errs []error
+		pkgs, errs = load(ctx, wd, env, opts.Tags, loadPatterns, true)
+		if len(errs) > 0 {
+			return nil, errs
+		}
 	}
-	generated := make([]GenerateResult, len(pkgs))
-	for i, pkg := range pkgs {
-		generated[i].PkgPath = pkg.PkgPath
+	// The results and the provider set usage are kept per directory, so that
+	// they can be stored in the cache.
+	var dirs []string
+	byDir := make(map[string][]GenerateResult)
+	usages := make(map[string]*setUsage)
+	dirUsage := func(outDir string) *setUsage {
+		u, ok := usages[outDir]
+		if !ok {
+			u = newSetUsage()
+			usages[outDir] = u
+			dirs = append(dirs, outDir)
+		}
+		return u
+	}
+	// testOutputs maps the path of each autowire_gen_test.go file to the
+	// package it was generated for.
+	testOutputs := make(map[string]string)
+	var remotes []remoteInjectors
+	for _, pkg := range pkgs {
+		if isTestMain(pkg) {
+			continue
+		}
 		outDir, err := detectOutputDir(pkg.GoFiles)
-		if err != nil {
-			generated[i].Errs = append(generated[i].Errs, err)
+		if !isTestVariant(pkg) {
+			if err != nil {
+				dirUsage(outDir)
+				byDir[outDir] = append(byDir[outDir], GenerateResult{PkgPath: pkg.PkgPath, Errs: []error{err}})
+				continue
+			}
+			files := make([]*ast.File, 0, len(pkg.Syntax))
+			for _, f := range pkg.Syntax {
+				if !isTestFile(pkg.Fset, f) {
+					files = append(files, f)
+				}
+			}
+			outputPath := filepath.Join(outDir, outputFile)
+			gen, remote := generate(pkg, files, outputPath, opts, dirUsage(outDir))
+			byDir[outDir] = append(byDir[outDir], gen)
+			if len(remote) > 0 {
+				remotes = append(remotes, remoteInjectors{pkg: pkg, outDir: outDir, injectors: remote})
+			}
 			continue
 		}
-		generated[i].OutputPath = filepath.Join(outDir, opts.PrefixOutputFile+"autowire_gen.go")
-		g := newGen(pkg)
-		injectorFiles, errs := generateInjectors(g, pkg)
-		if len(errs) > 0 {
-			generated[i].Errs = errs
+		var files []*ast.File
+		for _, f := range pkg.Syntax {
+			if isTestFile(pkg.Fset, f) {
+				files = append(files, f)
+			}
+		}
+		if len(files) == 0 || err != nil {
+			// Errors are reported for the package itself.
 			continue
 		}
-		copyNonInjectorDecls(g, injectorFiles, pkg.TypesInfo)
-		goSrc := g.frame(opts.Tags, opts.NoAddGenerateDirective)
-		if len(opts.Header) > 0 {
-			goSrc = append(opts.Header, goSrc...)
+		outputPath := filepath.Join(outDir, testOutputFile)
+		// The directive in the package's output file already regenerates this
+		// file.
+		testOpts := *opts
+		testOpts.NoAddGenerateDirective = true
+		// Test injectors can't use autowire.Into, so none are remote.
+		gen, _ := generate(pkg, files, outputPath, &testOpts, dirUsage(outDir))
+		if len(gen.Content) == 0 && len(gen.Errs) == 0 {
+			// No test injectors.
+			continue
 		}
-		fmtSrc, err := format.Source(goSrc)
-		if err != nil {
-			// This is likely a bug from a poorly generated source file.
-			// Add an error but also the unformatted source.
-			generated[i].Errs = append(generated[i].Errs, err)
-		} else {
-			goSrc = fmtSrc
+		if prev, ok := testOutputs[outputPath]; ok {
+			gen.Content = nil
+			gen.Errs = append(gen.Errs, fmt.Errorf("%s: test injectors are declared in both package %s and package %s; declare them in only one", outDir, prev, pkg.Name))
+		}
+		testOutputs[outputPath] = pkg.Name
+		byDir[outDir] = append(byDir[outDir], gen)
+	}
+	if len(remotes) > 0 {
+		for outDir, results := range generateInto(ctx, wd, env, outputFile, opts, remotes, dirUsage) {
+			byDir[outDir] = append(byDir[outDir], results...)
+		}
+	}
+
+	order := dirs
+	if cache != nil {
+		order = cache.dirs
+		for _, outDir := range dirs {
+			if _, ok := cache.keys[outDir]; !ok {
+				order = append(order, outDir)
+			}
+		}
+	}
+	generated := make([]GenerateResult, 0, len(pkgs))
+	usage := newSetUsage()
+	spans := make(map[string][2]int)
+	for _, outDir := range order {
+		start := len(generated)
+		if ent := cache.hit(outDir); ent != nil {
+			generated = append(generated, ent.results()...)
+			usage.mergeSnapshot(ent.Usage)
+			continue
+		}
+		generated = append(generated, byDir[outDir]...)
+		usage.merge(dirUsage(outDir))
+		spans[outDir] = [2]int{start, len(generated)}
+	}
+	reportUnused(generated, usage, opts.Strict)
+	if cache != nil {
+		for outDir, span := range spans {
+			cache.store(outDir, generated[span[0]:span[1]], usages[outDir])
 		}
-		generated[i].Content = goSrc
 	}
 	return generated, nil
 }
 
+// reportUnused adds a warning to generated for each member of a provider set
+// that no injector used, or an error if strict is true. The problem is
+// reported for the package that declares the set, and only if generation
+// succeeded, since an injector that failed does not record what it used.
+func reportUnused(generated []GenerateResult, usage *setUsage, strict bool) {
+	first := make(map[string]int)
+	for i := range generated {
+		if len(generated[i].Errs) > 0 {
+			return
+		}
+		if _, ok := first[generated[i].PkgPath]; !ok {
+			first[generated[i].PkgPath] = i
+		}
+	}
+	unused := usage.unused(func(pkgPath string) bool {
+		_, ok := first[pkgPath]
+		return ok
+	})
+	for pkgPath, warnings := range unused {
+		res := &generated[first[pkgPath]]
+		if !strict {
+			res.Warnings = append(res.Warnings, warnings...)
+			continue
+		}
+		res.Errs = append(res.Errs, warnings...)
+		for i := range generated {
+			if generated[i].PkgPath == pkgPath {
+				generated[i].Content = nil
+			}
+		}
+	}
+}
+
+// generate generates the injectors declared in the given files of pkg, and
+// records the provider set members they use in usage. The solved injectors
+// that use autowire.Into are returned instead of being generated.
+func generate(pkg *packages.Package, files []*ast.File, outputPath string, opts *GenerateOptions, usage *setUsage) (GenerateResult, []*injector) {
+	res := GenerateResult{PkgPath: pkg.PkgPath, OutputPath: outputPath}
+	g := newGen(pkg)
+	g.graphs = opts.Graphs
+	g.usage = usage
+	g.maxFieldDepth = opts.MaxFieldDepth
+	g.zeroFillBasics = opts.ZeroFillBasics
+	g.annotate = opts.Annotate
+	injectorFiles, remote, errs := generateInjectors(g, pkg, files)
+	if len(errs) > 0 {
+		res.Errs = errs
+		return res, nil
+	}
+	copyNonInjectorDecls(g, injectorFiles, pkg.TypesInfo)
+	g.format(&res, opts)
+	res.Warnings = g.warnings
+	return res, remote
+}
+
+// format sets res.Content to the formatted source of the generated file, if
+// there is anything to generate.
+func (g *gen) format(res *GenerateResult, opts *GenerateOptions) {
+	goSrc := g.frame(opts)
+	if len(goSrc) == 0 {
+		return
+	}
+	if len(opts.Header) > 0 {
+		goSrc = append(opts.Header, goSrc...)
+	}
+	fmtSrc, err := format.Source(goSrc)
+	if err != nil {
+		// This is likely a bug from a poorly generated source file.
+		// Add an error but also the unformatted source.
+		res.Errs = append(res.Errs, err)
+	} else {
+		goSrc = fmtSrc
+	}
+	res.Content = goSrc
+}
+
+// isTestVariant reports whether pkg is a package compiled for its own tests,
+// either the package augmented with its _test.go files or its external test
+// package.
+func isTestVariant(pkg *packages.Package) bool {
+	return pkg.ID != pkg.PkgPath
+}
+
+// isTestMain reports whether pkg is the synthesized main package of a test
+// binary.
+func isTestMain(pkg *packages.Package) bool {
+	return pkg.Name == "main" && strings.HasSuffix(pkg.PkgPath, ".test")
+}
+
+// isTestFile reports whether f is a _test.go file.
+func isTestFile(fset *token.FileSet, f *ast.File) bool {
+	return strings.HasSuffix(fset.File(f.Pos()).Name(), "_test.go")
+}
+
 func detectOutputDir(paths []string) (string, error) {
 	if len(paths) == 0 {
 		return "", errors.New("no files to derive output directory from")
@@ -136,11 +458,18 @@ func detectOutputDir(paths []string) (string, error) {
 }
 
 // generateInjectors generates the injectors for a given package.
-func generateInjectors(g *gen, pkg *packages.Package) (injectorFiles []*ast.File, _ []error) {
+// Only the injectors declared in files are generated. The injectors that use
+// autowire.Into are solved, but returned as remote instead of being generated.
+func generateInjectors(g *gen, pkg *packages.Package, files []*ast.File) (injectorFiles []*ast.File, remote []*injector, _ []error) {
 	oc := newObjectCache([]*packages.Package{pkg})
-	injectorFiles = make([]*ast.File, 0, len(pkg.Syntax))
-	ec := new(errorCollector)
-	for _, f := range pkg.Syntax {
+	oc.maxFieldDepth = g.maxFieldDepth
+	injectorFiles = make([]*ast.File, 0, len(files))
+	// The provider sets are processed in order, since the object cache is
+	// not safe for concurrent use. The injectors are then solved
+	// concurrently, and their code is generated in order.
+	var injectors []*injector
+	seen := make(map[*ProviderSet]bool)
+	for _, f := range files {
 		for _, decl := range f.Decls {
 			fn, ok := decl.(*ast.FuncDecl)
 			if !ok {
@@ -148,26 +477,32 @@ func generateInjectors(g *gen, pkg *packages.Package) (injectorFiles []*ast.File
 			}
 			buildCall, err := findInjectorBuild(pkg.TypesInfo, fn)
 			if err != nil {
-				ec.add(err)
+				injectors = append(injectors, &injector{errs: []error{err}})
 				continue
 			}
 			if buildCall == nil {
 				continue
 			}
-			if len(injectorFiles) == 0 || injectorFiles[len(injectorFiles)-1] != f {
-				// This is the first injector generated for this file.
-				// Write a file header.
-				name := filepath.Base(g.pkg.Fset.File(f.Pos()).Name())
-				g.p("// Injectors from %s:\n\n", name)
-				injectorFiles = append(injectorFiles, f)
+			inj := &injector{fn: fn, file: f, zeroFillBasics: g.zeroFillBasics}
+			injectors = append(injectors, inj)
+			inj.dest, buildCall, err = injectorDestination(pkg.TypesInfo, buildCall)
+			if err == nil && inj.dest != "" && isTestFile(pkg.Fset, f) {
+				err = errors.New("autowire.Into can't be used by test injectors")
+			}
+			if err != nil {
+				inj.errs = append(inj.errs, notePosition(g.pkg.Fset.Position(fn.Pos()), fmt.Errorf("inject %s: %v", fn.Name.Name, err)))
+				continue
+			}
+			if inj.dest == pkg.PkgPath {
+				inj.dest = ""
 			}
-			sig := pkg.TypesInfo.ObjectOf(fn.Name).Type().(*types.Signature)
-			ins, _, err := injectorFuncSignature(sig)
+			inj.sig = pkg.TypesInfo.ObjectOf(fn.Name).Type().(*types.Signature)
+			ins, _, err := injectorFuncSignature(inj.sig)
 			if err != nil {
 				if w, ok := err.(*wireErr); ok {
-					ec.add(notePosition(w.position, fmt.Errorf("inject %s: %v", fn.Name.Name, w.error)))
+					inj.errs = append(inj.errs, notePosition(w.position, fmt.Errorf("inject %s: %v", fn.Name.Name, w.error)))
 				} else {
-					ec.add(notePosition(g.pkg.Fset.Position(fn.Pos()), fmt.Errorf("inject %s: %v", fn.Name.Name, err)))
+					inj.errs = append(inj.errs, notePosition(g.pkg.Fset.Position(fn.Pos()), fmt.Errorf("inject %s: %v", fn.Name.Name, err)))
 				}
 				continue
 			}
@@ -178,25 +513,145 @@ func generateInjectors(g *gen, pkg *packages.Package) (injectorFiles []*ast.File
 			}
 			set, errs := oc.processNewSet(pkg.TypesInfo, pkg.PkgPath, buildCall, injectorArgs, "")
 			if len(errs) > 0 {
-				ec.add(notePositionAll(g.pkg.Fset.Position(fn.Pos()), errs)...)
-				continue
-			}
-			if errs := g.inject(fn.Pos(), fn.Name.Name, sig, set, fn.Doc); len(errs) > 0 {
-				ec.add(errs...)
+				inj.errs = notePositionAll(g.pkg.Fset.Position(fn.Pos()), errs)
 				continue
 			}
+			// The set's maps share the object cache's hasher, which memoizes
+			// hashes and so cannot be used by two injectors at once.
+			hasher := typeutil.MakeHasher()
+			set.providerMap = withHasher(set.providerMap, hasher)
+			set.srcMap = withHasher(set.srcMap, hasher)
+			set.defaultMap = withHasher(set.defaultMap, hasher)
+			inj.set = set
+			g.warnings = append(g.warnings, overrides(set, seen)...)
 		}
 
-		for _, impt := range f.Imports {
-			if impt.Name != nil && impt.Name.Name == "_" {
-				g.anonImports[impt.Path.Value] = true
-			}
+		g.addFileImports(f)
+	}
+	solveInjectors(g.pkg.Fset, injectors)
+
+	ec := new(errorCollector)
+	var headerFile *ast.File
+	for _, inj := range injectors {
+		if inj.file != nil && (len(injectorFiles) == 0 || injectorFiles[len(injectorFiles)-1] != inj.file) {
+			// The other declarations of the file are copied even if all of
+			// its injectors are generated elsewhere.
+			injectorFiles = append(injectorFiles, inj.file)
+		}
+		if len(inj.errs) > 0 {
+			ec.add(inj.errs...)
+			continue
+		}
+		if inj.dest != "" {
+			remote = append(remote, inj)
+			continue
+		}
+		if inj.file != headerFile {
+			// This is the first injector generated for this file.
+			// Write a file header.
+			name := filepath.Base(g.pkg.Fset.File(inj.file.Pos()).Name())
+			g.p("// Injectors from %s:\n\n", name)
+			headerFile = inj.file
+		}
+		if errs := g.inject(inj); len(errs) > 0 {
+			ec.add(errs...)
+			continue
 		}
 	}
 	if len(ec.errors) > 0 {
-		return nil, ec.errors
+		return nil, nil, ec.errors
+	}
+	return injectorFiles, remote, nil
+}
+
+// injector is an injector function declared in a package, along with the
+// result of solving it.
+type injector struct {
+	fn   *ast.FuncDecl
+	file *ast.File
+	sig  *types.Signature
+	set  *ProviderSet
+	// dest is the import path given to autowire.Into, or empty if the
+	// injector is generated into the package that declares it.
+	dest string
+	// zeroFillBasics is GenerateOptions.ZeroFillBasics.
+	zeroFillBasics bool
+
+	injectSig outputSignature
+	calls     []call
+	// out is the index of the output among the parameters and calls.
+	out  int
+	used *typeutil.Map
+	errs []error
+}
+
+// solveInjectors solves each injector that has no errors yet, using up to
+// GOMAXPROCS goroutines. Each injector only records its own results, so the
+// errors are reported in declaration order regardless of scheduling.
+//
+// The members of each injector's set are checked to be used afterwards, one
+// injector at a time, since that looks types up in the imported sets, whose
+// maps share the object cache's hasher.
+func solveInjectors(fset *token.FileSet, injectors []*injector) {
+	work := make(chan *injector)
+	n := runtime.GOMAXPROCS(0)
+	if n > len(injectors) {
+		n = len(injectors)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for inj := range work {
+				inj.solve(fset)
+			}
+		}()
+	}
+	for _, inj := range injectors {
+		if len(inj.errs) == 0 {
+			work <- inj
+		}
+	}
+	close(work)
+	wg.Wait()
+	for _, inj := range injectors {
+		if len(inj.errs) == 0 {
+			if errs := verifyArgsUsed(inj.set, inj.used); len(errs) > 0 {
+				inj.fail(fset, errs)
+			}
+		}
 	}
-	return injectorFiles, nil
+}
+
+// solve determines the calls that inj makes to produce its output.
+func (inj *injector) solve(fset *token.FileSet) {
+	name := inj.fn.Name.Name
+	pos := inj.fn.Pos()
+	injectSig, err := funcOutput(inj.sig)
+	if err != nil {
+		inj.errs = []error{notePosition(fset.Position(pos),
+			fmt.Errorf("inject %s: %v", name, err))}
+		return
+	}
+	inj.injectSig = injectSig
+	calls, out, used, errs := solve(fset, injectSig.out, inj.sig.Params(), inj.set, inj.zeroFillBasics)
+	if len(errs) > 0 {
+		inj.fail(fset, errs)
+		return
+	}
+	inj.calls, inj.out, inj.used = calls, out, used
+}
+
+// fail records errs as the errors of inj, prefixed with the injector's name.
+func (inj *injector) fail(fset *token.FileSet, errs []error) {
+	name := inj.fn.Name.Name
+	inj.errs = mapErrors(errs, func(e error) error {
+		if w, ok := e.(*wireErr); ok {
+			return notePosition(w.position, fmt.Errorf("inject %s: %v", name, w.error))
+		}
+		return notePosition(fset.Position(inj.fn.Pos()), fmt.Errorf("inject %s: %v", name, e))
+	})
 }
 
 // copyNonInjectorDecls copies any non-injector declarations from the
@@ -246,33 +701,58 @@ type gen struct {
 	buf         bytes.Buffer
 	imports     map[string]importInfo
 	anonImports map[string]bool
-	values      map[ast.Expr]string
+	// aliases maps an import path to the name that the package declaring the
+	// injectors imports it as, if one of its files names the import.
+	aliases map[string]string
+	values  map[ast.Expr]string
+	graphs  GraphWriter
+	usage   *setUsage
+	// maxFieldDepth is GenerateOptions.MaxFieldDepth.
+	maxFieldDepth int
+	// zeroFillBasics is GenerateOptions.ZeroFillBasics.
+	zeroFillBasics bool
+	// annotate is GenerateOptions.Annotate.
+	annotate bool
+	// from is the import path of the package that declares the injectors,
+	// if they are generated into another package with autowire.Into.
+	from string
+	// warnings holds the providers replaced by test injectors, which are
+	// reported as GenerateResult.Warnings.
+	warnings []error
 }
 
 func newGen(pkg *packages.Package) *gen {
 	return &gen{
 		pkg:         pkg,
 		anonImports: make(map[string]bool),
+		aliases:     make(map[string]string),
 		imports:     make(map[string]importInfo),
 		values:      make(map[ast.Expr]string),
 	}
 }
 
 // frame bakes the built up source body into an unformatted Go source file.
-func (g *gen) frame(tags string, noAddGenerateDirective bool) []byte {
+func (g *gen) frame(opts *GenerateOptions) []byte {
 	if g.buf.Len() == 0 {
 		return nil
 	}
 	var buf bytes.Buffer
-	if len(tags) > 0 {
-		tags = fmt.Sprintf(" gen -tags \"%s\"", tags)
-	}
 	buf.WriteString("// Code generated by Autowire. DO NOT EDIT.\n\n")
-	if !noAddGenerateDirective {
-		buf.WriteString("//go:generate go run github.com/dabbertorres/autowire/cmd/autowire" + tags + "\n\n")
+	if !opts.NoAddGenerateDirective {
+		directive := opts.generateDirective()
+		if g.from != "" {
+			// Regenerate the package that declares the injectors.
+			if directive == "" {
+				directive = " gen"
+			}
+			directive += " " + g.from
+		}
+		buf.WriteString("//go:generate go run github.com/dabbertorres/autowire/cmd/autowire" + directive + "\n\n")
+	}
+	if g.from == "" {
+		buf.WriteString("//go:build !wireinject\n")
+		buf.WriteString("// +build !wireinject\n\n")
 	}
-	buf.WriteString("//go:build !wireinject\n")
-	buf.WriteString("// +build !wireinject\n\n")
 	buf.WriteString("package ")
 	buf.WriteString(g.pkg.Name)
 	buf.WriteString("\n\n")
@@ -312,21 +792,20 @@ func (g *gen) frame(tags string, noAddGenerateDirective bool) []byte {
 }
 
 // inject emits the code for an injector.
-func (g *gen) inject(pos token.Pos, name string, sig *types.Signature, set *ProviderSet, doc *ast.CommentGroup) []error {
-	injectSig, err := funcOutput(sig)
-	if err != nil {
-		return []error{notePosition(g.pkg.Fset.Position(pos),
-			fmt.Errorf("inject %s: %v", name, err))}
-	}
-	params := sig.Params()
-	calls, errs := solve(g.pkg.Fset, injectSig.out, params, set)
-	if len(errs) > 0 {
-		return mapErrors(errs, func(e error) error {
-			if w, ok := e.(*wireErr); ok {
-				return notePosition(w.position, fmt.Errorf("inject %s: %v", name, w.error))
-			}
-			return notePosition(g.pkg.Fset.Position(pos), fmt.Errorf("inject %s: %v", name, e))
-		})
+func (g *gen) inject(inj *injector) []error {
+	pos, name, sig, set, doc := inj.fn.Pos(), inj.fn.Name.Name, inj.sig, inj.set, inj.fn.Doc
+	injectSig, calls, out := inj.injectSig, inj.calls, inj.out
+	if g.usage != nil {
+		g.usage.record(g.pkg.Fset, set, inj.used)
+	}
+	if g.graphs != nil {
+		pkgPath := g.pkg.PkgPath
+		if g.from != "" {
+			pkgPath = g.from
+		}
+		if err := g.graphs.WriteGraph(injectorGraph(g.pkg.Fset, pkgPath, name, pos, sig.Params(), calls)); err != nil {
+			return []error{fmt.Errorf("inject %s: write graph: %v", name, err)}
+		}
 	}
 	type pendingVar struct {
 		name     string
@@ -335,24 +814,39 @@ func (g *gen) inject(pos token.Pos, name string, sig *types.Signature, set *Prov
 	}
 	var pendingVars []pendingVar
 	ec := new(errorCollector)
+	for i := 0; i < sig.Params().Len(); i++ {
+		if err := accessibleType(sig.Params().At(i).Type(), g.pkg.PkgPath); err != nil {
+			ec.add(notePosition(g.pkg.Fset.Position(pos), fmt.Errorf("inject %s: %v", name, err)))
+		}
+	}
+	if err := accessibleType(injectSig.out, g.pkg.PkgPath); err != nil {
+		ec.add(notePosition(g.pkg.Fset.Position(pos), fmt.Errorf("inject %s: %v", name, err)))
+	}
 	for i := range calls {
 		c := &calls[i]
 		if c.hasCleanup && !injectSig.cleanup {
-			ts := types.TypeString(c.out, nil)
+			ts := typeString(c.out, nil)
 			ec.add(notePosition(
 				g.pkg.Fset.Position(pos),
 				fmt.Errorf("inject %s: provider for %s returns cleanup but injection does not return cleanup function", name, ts)))
 		}
-		if c.hasErr && !injectSig.err {
-			ts := types.TypeString(c.out, nil)
+		if c.hasErr && !injectSig.err && c.kind == hookCall {
+			ec.add(notePosition(
+				g.pkg.Fset.Position(pos),
+				fmt.Errorf("inject %s: hook %s returns error but injection not allowed to fail", name, c.name)))
+		} else if c.hasErr && !injectSig.err {
+			ts := typeString(c.out, nil)
 			ec.add(notePosition(
 				g.pkg.Fset.Position(pos),
 				fmt.Errorf("inject %s: provider for %s returns error but injection not allowed to fail", name, ts)))
 		}
+		if err := accessibleCall(c, g.pkg.PkgPath); err != nil {
+			ec.add(notePosition(g.pkg.Fset.Position(pos), fmt.Errorf("inject %s: %v", name, err)))
+		}
 		if c.kind == valueExpr {
 			if err := accessibleFrom(c.valueTypeInfo, c.valueExpr, g.pkg.PkgPath); err != nil {
 				// TODO(light): Display line number of value expression.
-				ts := types.TypeString(c.out, nil)
+				ts := typeString(c.out, nil)
 				ec.add(notePosition(
 					g.pkg.Fset.Position(pos),
 					fmt.Errorf("inject %s: value %s can't be used: %v", name, ts, err)))
@@ -375,12 +869,12 @@ func (g *gen) inject(pos token.Pos, name string, sig *types.Signature, set *Prov
 	}
 
 	// Perform one pass to collect all imports, followed by the real pass.
-	injectPass(name, sig, calls, set, doc, &injectorGen{
+	injectPass(name, sig, calls, out, doc, &injectorGen{
 		g:       g,
 		errVar:  disambiguate("err", g.nameInFileScope),
 		discard: true,
 	})
-	injectPass(name, sig, calls, set, doc, &injectorGen{
+	injectPass(name, sig, calls, out, doc, &injectorGen{
 		g:       g,
 		errVar:  disambiguate("err", g.nameInFileScope),
 		discard: false,
@@ -543,11 +1037,35 @@ func (g *gen) qualifyImport(name, path string) string {
 	if info, ok := g.imports[unvendored]; ok {
 		return info.name
 	}
-	// TODO(light): Use parts of import path to disambiguate.
-	newName := disambiguate(name, func(n string) bool {
+	collides := func(n string) bool {
 		// Don't let an import take the "err" name. That's annoying.
 		return n == "err" || g.nameInFileScope(n)
-	})
+	}
+	var names []string
+	if alias := g.aliases[unvendored]; alias != "" {
+		// Use the same name as the file that declares the injectors.
+		names = append(names, alias)
+	}
+	names = append(names, name)
+	// Prefix the name with the directories above the package in its import
+	// path, such as "barconfig" for example.com/bar/config, before falling
+	// back to a counter.
+	elems := strings.Split(unvendored, "/")
+	prefixed := name
+	for i := len(elems) - 2; i >= 0 && token.IsIdentifier(elems[i]); i-- {
+		prefixed = elems[i] + prefixed
+		names = append(names, prefixed)
+	}
+	newName := ""
+	for _, n := range names {
+		if !token.Lookup(n).IsKeyword() && !collides(n) {
+			newName = n
+			break
+		}
+	}
+	if newName == "" {
+		newName = disambiguate(name, collides)
+	}
 	g.imports[unvendored] = importInfo{
 		name:    newName,
 		differs: newName != name,
@@ -555,6 +1073,27 @@ func (g *gen) qualifyImport(name, path string) string {
 	return newName
 }
 
+// addFileImports records the blank imports and the import names of f, a file
+// of the package that declares the injectors. If files name the same import
+// differently, the name from the first one is used.
+func (g *gen) addFileImports(f *ast.File) {
+	for _, impt := range f.Imports {
+		if impt.Name == nil {
+			continue
+		}
+		switch impt.Name.Name {
+		case "_":
+			g.anonImports[impt.Path.Value] = true
+		case ".":
+		default:
+			path, err := strconv.Unquote(impt.Path.Value)
+			if _, ok := g.aliases[path]; err == nil && !ok {
+				g.aliases[path] = impt.Name.Name
+			}
+		}
+	}
+}
+
 func (g *gen) nameInFileScope(name string) bool {
 	for _, other := range g.imports {
 		if other.name == name {
@@ -592,9 +1131,10 @@ type injectorGen struct {
 	discard bool
 }
 
-// injectPass generates an injector given the output from analysis.
+// injectPass generates an injector given the output from analysis, where out
+// is the index of the output among the parameters and calls.
 // The sig passed in should be verified.
-func injectPass(name string, sig *types.Signature, calls []call, set *ProviderSet, doc *ast.CommentGroup, ig *injectorGen) {
+func injectPass(name string, sig *types.Signature, calls []call, out int, doc *ast.CommentGroup, ig *injectorGen) {
 	params := sig.Params()
 	injectSig, err := funcOutput(sig)
 	if err != nil {
@@ -638,9 +1178,26 @@ func injectPass(name string, sig *types.Signature, calls []call, set *ProviderSe
 	default:
 		ig.p(") %s {\n", outTypeString)
 	}
+	bodyStart := ig.g.buf.Len()
 	for i := range calls {
 		c := &calls[i]
+		if c.kind == setterCall {
+			// The setter modifies its target instead of declaring a variable.
+			ig.localNames = append(ig.localNames, ig.argName(c.args[0]))
+			ig.setterCall(c)
+			continue
+		}
+		if c.kind == hookCall {
+			// The hook does not produce a value, so nothing refers to its name.
+			ig.localNames = append(ig.localNames, "")
+			ig.hookCall(c, injectSig)
+			continue
+		}
 		lname := typeVariableName(c.out, "v", unexport, ig.nameInInjector)
+		if c.lazy {
+			// Name the factory after what it builds, like "newDB".
+			lname = typeVariableName(lazyResult(c.out), "v", func(name string) string { return "new" + strings.Title(name) }, ig.nameInInjector)
+		}
 		ig.localNames = append(ig.localNames, lname)
 		switch c.kind {
 		case structProvider:
@@ -651,15 +1208,18 @@ func injectPass(name string, sig *types.Signature, calls []call, set *ProviderSe
 			ig.valueExpr(lname, c)
 		case selectorExpr:
 			ig.fieldExpr(lname, c)
+		case sliceLiteral:
+			ig.sliceLiteral(lname, c)
+		case nilValue, zeroFill:
+			ig.p("\tvar %s %s\n", lname, types.TypeString(c.out, ig.g.qualifyPkg))
 		default:
 			panic("unknown kind")
 		}
 	}
-	if len(calls) == 0 {
-		ig.p("\treturn %s", ig.paramNames[set.For(injectSig.out).Arg().Index])
-	} else {
-		ig.p("\treturn %s", ig.localNames[len(calls)-1])
+	if ig.g.annotate && len(calls) > 0 {
+		ig.annotate(name, params, calls, bodyStart)
 	}
+	ig.p("\treturn %s", ig.argName(out))
 	if injectSig.cleanup {
 		ig.p(", func() {\n")
 		for i := len(ig.cleanupNames) - 1; i >= 0; i-- {
@@ -675,6 +1235,15 @@ func injectPass(name string, sig *types.Signature, calls []call, set *ProviderSe
 
 func (ig *injectorGen) funcProviderCall(lname string, c *call, injectSig outputSignature) {
 	ig.p("\t%s", lname)
+	if c.lazy {
+		// The factory returns exactly what the provider does, so it can
+		// return the call's results as they are, cleanup and error included.
+		ig.p(" := %s {\n", types.TypeString(lazyFactory(c.out), ig.g.qualifyPkg))
+		ig.p("\t\treturn ")
+		ig.providerCallExpr(c)
+		ig.p("\t}\n")
+		return
+	}
 	prevCleanup := len(ig.cleanupNames)
 	if c.hasCleanup {
 		cname := disambiguate("cleanup", ig.nameInInjector)
@@ -685,21 +1254,7 @@ func (ig *injectorGen) funcProviderCall(lname string, c *call, injectSig outputS
 		ig.p(", %s", ig.errVar)
 	}
 	ig.p(" := ")
-	ig.p("%s(", ig.g.qualifiedID(c.pkg.Name(), c.pkg.Path(), c.name))
-	for i, a := range c.args {
-		if i > 0 {
-			ig.p(", ")
-		}
-		if a < len(ig.paramNames) {
-			ig.p("%s", ig.paramNames[a])
-		} else {
-			ig.p("%s", ig.localNames[a-len(ig.paramNames)])
-		}
-	}
-	if c.varargs {
-		ig.p("...")
-	}
-	ig.p(")\n")
+	ig.providerCallExpr(c)
 	if c.hasErr {
 		ig.p("\tif %s != nil {\n", ig.errVar)
 		for i := prevCleanup - 1; i >= 0; i-- {
@@ -715,6 +1270,185 @@ func (ig *injectorGen) funcProviderCall(lname string, c *call, injectSig outputS
 	}
 }
 
+// providerCallExpr writes the call of the provider function for c, followed
+// by a newline.
+func (ig *injectorGen) providerCallExpr(c *call) {
+	args := c.args
+	if c.method {
+		ig.p("%s.%s", ig.argName(args[0]), c.name)
+		args = args[1:]
+	} else {
+		ig.p("%s", ig.g.qualifiedID(c.pkg.Name(), c.pkg.Path(), c.name))
+	}
+	if len(c.typeArgs) > 0 {
+		ig.p("[")
+		for i, t := range c.typeArgs {
+			if i > 0 {
+				ig.p(", ")
+			}
+			ig.p("%s", types.TypeString(t, ig.g.qualifyPkg))
+		}
+		ig.p("]")
+	}
+	ig.p("(")
+	for i, a := range args {
+		if i > 0 {
+			ig.p(", ")
+		}
+		ig.p("%s", ig.argName(a))
+	}
+	if c.varargs {
+		ig.p("...")
+	}
+	ig.p(")\n")
+}
+
+// lazyFactory returns the factory type of a lazy provider that provides t,
+// which is t itself unless the provider was qualified with autowire.Named.
+func lazyFactory(t types.Type) *types.Signature {
+	if _, qt, ok := qualifier(t); ok {
+		t = qt
+	}
+	return t.(*types.Signature)
+}
+
+// lazyResult returns the type of the value built by the factory of type t.
+func lazyResult(t types.Type) types.Type {
+	return lazyFactory(t).Results().At(0).Type()
+}
+
+// annotate inserts a comment at bodyStart in the generated file that lists
+// the steps of the injector in order, along with the kind and position of
+// what makes each one, as recorded in the injector's graph.
+func (ig *injectorGen) annotate(name string, params *types.Tuple, calls []call, bodyStart int) {
+	if ig.discard {
+		return
+	}
+	fset := ig.g.pkg.Fset
+	nodes := injectorGraph(fset, ig.g.pkg.PkgPath, name, token.NoPos, params, calls).Nodes[params.Len():]
+	sb := new(strings.Builder)
+	sb.WriteString("\t// Build order:\n")
+	for i := range calls {
+		pos := nodes[i].Pos
+		fmt.Fprintf(sb, "\t//\t%s (%s, %s:%d)\n", ig.describeCall(&calls[i], ig.localNames[i]), nodes[i].Kind, filepath.Base(pos.Filename), pos.Line)
+	}
+	body := append([]byte(nil), ig.g.buf.Bytes()[bodyStart:]...)
+	ig.g.buf.Truncate(bodyStart)
+	ig.g.buf.WriteString(sb.String())
+	ig.g.buf.Write(body)
+}
+
+// describeCall returns a one-line summary of the code generated for c, whose
+// result is named lname.
+func (ig *injectorGen) describeCall(c *call, lname string) string {
+	args := make([]string, len(c.args))
+	for i, a := range c.args {
+		args[i] = ig.argName(a)
+	}
+	switch c.kind {
+	case funcProviderCall:
+		fn := ig.g.qualifiedID(c.pkg.Name(), c.pkg.Path(), c.name)
+		if c.method {
+			fn, args = args[0]+"."+c.name, args[1:]
+		}
+		call := fn + "(" + strings.Join(args, ", ")
+		if c.varargs {
+			call += "..."
+		}
+		if c.lazy {
+			return lname + " = func() { return " + call + ") }"
+		}
+		return lname + " = " + call + ")"
+	case structProvider:
+		fields := make([]string, len(args))
+		for i := range args {
+			fields[i] = c.fieldNames[i] + ": " + args[i]
+		}
+		lit := ig.g.qualifiedID(c.pkg.Name(), c.pkg.Path(), c.name) + "{" + strings.Join(fields, ", ") + "}"
+		if _, ok := c.out.(*types.Pointer); ok {
+			lit = "&" + lit
+		}
+		return lname + " = " + lit
+	case valueExpr:
+		return lname + " = " + types.ExprString(c.valueExpr)
+	case selectorExpr:
+		if c.ptrToField {
+			return lname + " = &" + args[0] + "." + c.name
+		}
+		return lname + " = " + args[0] + "." + c.name
+	case sliceLiteral:
+		return lname + " = " + types.TypeString(c.out, ig.g.qualifyPkg) + "{" + strings.Join(args, ", ") + "}"
+	case nilValue:
+		return lname + " = nil"
+	case zeroFill:
+		return lname + " = " + zeroValue(c.out, ig.g.qualifyPkg)
+	case setterCall:
+		if c.method {
+			return args[0] + "." + c.name + "(" + args[1] + ")"
+		}
+		return ig.g.qualifiedID(c.pkg.Name(), c.pkg.Path(), c.name) + "(" + strings.Join(args, ", ") + ")"
+	case hookCall:
+		if c.method {
+			return args[0] + "." + c.name + "(" + strings.Join(args[1:], ", ") + ")"
+		}
+		return ig.g.qualifiedID(c.pkg.Name(), c.pkg.Path(), c.name) + "(" + strings.Join(args, ", ") + ")"
+	}
+	panic("unknown kind")
+}
+
+func (ig *injectorGen) setterCall(c *call) {
+	if c.method {
+		ig.p("\t%s.%s(%s)\n", ig.argName(c.args[0]), c.name, ig.argName(c.args[1]))
+		return
+	}
+	ig.p("\t%s(%s, %s)\n", ig.g.qualifiedID(c.pkg.Name(), c.pkg.Path(), c.name), ig.argName(c.args[0]), ig.argName(c.args[1]))
+}
+
+func (ig *injectorGen) hookCall(c *call, injectSig outputSignature) {
+	ig.p("\t")
+	if c.hasErr {
+		ig.p("if %s := ", ig.errVar)
+	}
+	args := c.args
+	if c.method {
+		ig.p("%s.%s", ig.argName(args[0]), c.name)
+		args = args[1:]
+	} else {
+		ig.p("%s", ig.g.qualifiedID(c.pkg.Name(), c.pkg.Path(), c.name))
+	}
+	ig.p("(")
+	for i, a := range args {
+		if i > 0 {
+			ig.p(", ")
+		}
+		ig.p("%s", ig.argName(a))
+	}
+	ig.p(")")
+	if !c.hasErr {
+		ig.p("\n")
+		return
+	}
+	ig.p("; %s != nil {\n", ig.errVar)
+	for i := len(ig.cleanupNames) - 1; i >= 0; i-- {
+		ig.p("\t\t%s()\n", ig.cleanupNames[i])
+	}
+	ig.p("\t\treturn %s", zeroValue(injectSig.out, ig.g.qualifyPkg))
+	if injectSig.cleanup {
+		ig.p(", nil")
+	}
+	ig.p(", %s\n", ig.errVar)
+	ig.p("\t}\n")
+}
+
+// argName returns the name of the variable holding the argument with index a,
+// which is either an injector parameter or the result of an earlier call.
+func (ig *injectorGen) argName(a int) string {
+	if a < len(ig.paramNames) {
+		return ig.paramNames[a]
+	}
+	return ig.localNames[a-len(ig.paramNames)]
+}
+
 func (ig *injectorGen) structProviderCall(lname string, c *call) {
 	ig.p("\t%s", lname)
 	ig.p(" := ")
@@ -751,6 +1485,21 @@ func (ig *injectorGen) fieldExpr(lname string, c *call) {
 	}
 }
 
+func (ig *injectorGen) sliceLiteral(lname string, c *call) {
+	ig.p("\t%s := %s{", lname, types.TypeString(c.out, ig.g.qualifyPkg))
+	for i, a := range c.args {
+		if i > 0 {
+			ig.p(", ")
+		}
+		if a < len(ig.paramNames) {
+			ig.p("%s", ig.paramNames[a])
+		} else {
+			ig.p("%s", ig.localNames[a-len(ig.paramNames)])
+		}
+	}
+	ig.p("}\n")
+}
+
 // nameInInjector reports whether name collides with any other identifier
 // in the current injector.
 func (ig *injectorGen) nameInInjector(name string) bool {
@@ -827,12 +1576,26 @@ func typeVariableName(t types.Type, defaultName string, transform func(string) s
 	case *types.Named:
 		obj := t.Obj()
 		if name := obj.Name(); name != "" {
+			if _, _, ok := qualifier(t); ok && !token.IsIdentifier(name) {
+				// Name the value of an option by its provider.
+				name = name[strings.LastIndex(name, ".")+1:]
+			}
 			names = append(names, name)
 		}
 		// Provide an alternate name prefixed with the package name if possible.
 		// E.g., in case of collisions, we'll use "fooCfg" instead of "cfg2".
 		if pkg := obj.Pkg(); pkg != nil && pkg.Name() != "" {
-			names = append(names, fmt.Sprintf("%s%s", pkg.Name(), strings.Title(obj.Name())))
+			name := fmt.Sprintf("%s%s", pkg.Name(), strings.Title(obj.Name()))
+			names = append(names, name)
+			// If that collides too, prefix it with the directories above the
+			// package in its import path before falling back to a counter.
+			// E.g., "barServiceService" for example.com/bar/service.Service,
+			// which does not change when other names are added or removed.
+			elems := strings.Split(pkg.Path(), "/")
+			for i := len(elems) - 2; i >= 0 && token.IsIdentifier(elems[i]); i-- {
+				name = elems[i] + strings.Title(name)
+				names = append(names, name)
+			}
 		}
 	}
 
@@ -25,6 +25,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 	"unicode"
@@ -88,18 +90,32 @@ func TestWire(t *testing.T) {
 				t.Fatal(err)
 			}
 			wd := filepath.Join(gopath, "src", "example.com")
-			gens, errs := Generate(ctx, wd, append(os.Environ(), "GOPATH="+gopath), []string{test.pkg}, &GenerateOptions{Header: test.header})
-			var gen GenerateResult
-			if len(gens) > 1 {
-				t.Fatalf("got %d generated files, want 0 or 1", len(gens))
+			opts := &GenerateOptions{Header: test.header, MaxFieldDepth: test.maxFieldDepth, ZeroFillBasics: test.zeroFillBasics, Annotate: test.annotate}
+			debugOut := new(bytes.Buffer)
+			if test.wantDebug {
+				opts.Graphs = NewTextGraphWriter(debugOut)
 			}
-			if len(gens) == 1 {
-				gen = gens[0]
-				if len(gen.Errs) > 0 {
-					errs = append(errs, gen.Errs...)
+			gens, errs := Generate(ctx, wd, append(os.Environ(), "GOPATH="+gopath), []string{test.pkg}, opts)
+			var gen, testGen GenerateResult
+			if len(gens) > 2 {
+				t.Fatalf("got %d generated files, want 0, 1 or 2", len(gens))
+			}
+			var gotWarnings []string
+			for _, g := range gens {
+				if len(g.Errs) > 0 {
+					errs = append(errs, g.Errs...)
+				}
+				for _, w := range g.Warnings {
+					gotWarnings = append(gotWarnings, scrubError(gopath, w.Error()))
 				}
-				if len(gen.Content) > 0 {
-					defer t.Logf("autowire_gen.go:\n%s", gen.Content)
+				name := filepath.Base(g.OutputPath)
+				if len(g.Content) > 0 {
+					defer t.Logf("%s:\n%s", name, g.Content)
+				}
+				if name == "autowire_gen_test.go" {
+					testGen = g
+				} else {
+					gen = g
 				}
 			}
 			if len(errs) > 0 {
@@ -126,6 +142,19 @@ func TestWire(t *testing.T) {
 			if test.wantWireError {
 				t.Fatal("autowire succeeded; want error")
 			}
+			if len(gotWarnings) > 0 && !test.wantWarnings {
+				t.Fatalf("Did not expect warnings. To -record warnings, create want/autowire_warnings.txt.\n%s", strings.Join(gotWarnings, "\n\n"))
+			}
+			if test.wantWarnings {
+				if *record {
+					warningsFile := filepath.Join(testRoot, test.name, "want", "autowire_warnings.txt")
+					if err := ioutil.WriteFile(warningsFile, []byte(strings.Join(gotWarnings, "\n\n")), 0666); err != nil {
+						t.Fatalf("failed to write autowire_warnings.txt file: %v", err)
+					}
+				} else if diff := cmp.Diff(gotWarnings, test.wantWarningStrings); diff != "" {
+					t.Errorf("Warnings didn't match expected warnings from autowire_warnings.txt:\n%s", diff)
+				}
+			}
 			outPathSane := true
 			if prefix := gopath + string(os.PathSeparator) + "src" + string(os.PathSeparator); !strings.HasPrefix(gen.OutputPath, prefix) {
 				outPathSane = false
@@ -143,13 +172,33 @@ func TestWire(t *testing.T) {
 				if err := gen.Commit(); err != nil {
 					t.Fatalf("failed to write autowire_gen.go to test GOPATH: %v", err)
 				}
+				if err := testGen.Commit(); err != nil {
+					t.Fatalf("failed to write autowire_gen_test.go to test GOPATH: %v", err)
+				}
 				if err := goBuildCheck(goToolPath, gopath, test); err != nil {
 					t.Fatalf("go build check failed: %v", err)
 				}
+				if len(testGen.Content) > 0 {
+					if err := goTestCheck(goToolPath, gopath, test); err != nil {
+						t.Fatalf("go test check failed: %v", err)
+					}
+				}
 				testdataWireGenPath := filepath.Join(testRoot, test.name, "want", "autowire_gen.go")
 				if err := ioutil.WriteFile(testdataWireGenPath, gen.Content, 0666); err != nil {
 					t.Fatalf("failed to record autowire_gen.go to testdata: %v", err)
 				}
+				testdataWireGenTestPath := filepath.Join(testRoot, test.name, "want", "autowire_gen_test.go")
+				if len(testGen.Content) > 0 {
+					if err := ioutil.WriteFile(testdataWireGenTestPath, testGen.Content, 0666); err != nil {
+						t.Fatalf("failed to record autowire_gen_test.go to testdata: %v", err)
+					}
+				}
+				if test.wantDebug {
+					debugOutPath := filepath.Join(testRoot, test.name, "want", "debug_out.txt")
+					if err := ioutil.WriteFile(debugOutPath, []byte(scrubError(gopath, debugOut.String())), 0666); err != nil {
+						t.Fatalf("failed to record debug_out.txt to testdata: %v", err)
+					}
+				}
 			} else {
 				// Replay ==> Load golden file and compare to
 				// generated result. This check is meant to
@@ -160,6 +209,15 @@ func TestWire(t *testing.T) {
 					diff := cmp.Diff(strings.Split(gotS, "\n"), strings.Split(wantS, "\n"))
 					t.Fatalf("autowire output differs from golden file. If this change is expected, run with -record to update the autowire_gen.go file.\n*** got:\n%s\n\n*** want:\n%s\n\n*** diff:\n%s", gotS, wantS, diff)
 				}
+				if !bytes.Equal(testGen.Content, test.wantWireTestOutput) {
+					gotS, wantS := string(testGen.Content), string(test.wantWireTestOutput)
+					diff := cmp.Diff(strings.Split(gotS, "\n"), strings.Split(wantS, "\n"))
+					t.Fatalf("autowire test output differs from golden file. If this change is expected, run with -record to update the autowire_gen_test.go file.\n*** got:\n%s\n\n*** want:\n%s\n\n*** diff:\n%s", gotS, wantS, diff)
+				}
+				if got := scrubError(gopath, debugOut.String()); test.wantDebug && got != string(test.wantDebugOutput) {
+					diff := cmp.Diff(strings.Split(got, "\n"), strings.Split(string(test.wantDebugOutput), "\n"))
+					t.Fatalf("dependency graph differs from golden file. If this change is expected, run with -record to update the debug_out.txt file.\n*** got:\n%s\n\n*** want:\n%s\n\n*** diff:\n%s", got, test.wantDebugOutput, diff)
+				}
 			}
 		})
 	}
@@ -194,6 +252,17 @@ func goBuildCheck(goToolPath, gopath string, test *testCase) error {
 	return nil
 }
 
+func goTestCheck(goToolPath, gopath string, test *testCase) error {
+	// Run `go test`, which uses the generated test injectors.
+	cmd := exec.Command(goToolPath, "test", test.pkg)
+	cmd.Dir = filepath.Join(gopath, "src", "example.com")
+	cmd.Env = append(os.Environ(), "GOPATH="+gopath)
+	if testOut, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("test: %v; output:\n%s", err, testOut)
+	}
+	return nil
+}
+
 func TestUnexport(t *testing.T) {
 	tests := []struct {
 		name string
@@ -252,13 +321,529 @@ func TestExport(t *testing.T) {
 	}
 }
 
+func TestOutputFiles(t *testing.T) {
+	tests := []struct {
+		opts          GenerateOptions
+		want          string
+		wantTest      string
+		wantDirective string
+		wantErr       bool
+	}{
+		{
+			opts:     GenerateOptions{},
+			want:     "autowire_gen.go",
+			wantTest: "autowire_gen_test.go",
+		},
+		{
+			opts:          GenerateOptions{OutputFile: "wiring_gen.go"},
+			want:          "wiring_gen.go",
+			wantTest:      "wiring_gen_test.go",
+			wantDirective: ` gen -output "wiring_gen.go"`,
+		},
+		{
+			opts:          GenerateOptions{PrefixOutputFile: "x_", OutputFile: "wiring_gen.go", Tags: "dev"},
+			want:          "x_wiring_gen.go",
+			wantTest:      "x_wiring_gen_test.go",
+			wantDirective: ` gen -output-file-prefix "x_" -output "wiring_gen.go" -tags "dev"`,
+		},
+		{
+			opts:          GenerateOptions{ZeroFillBasics: true},
+			want:          "autowire_gen.go",
+			wantTest:      "autowire_gen_test.go",
+			wantDirective: ` gen -zero-fill-basics`,
+		},
+		{
+			opts:          GenerateOptions{PrefixOutputFile: "x_", GOOS: "linux", GOARCH: "arm64"},
+			want:          "x_autowire_gen_linux_arm64.go",
+			wantTest:      "x_autowire_gen_linux_arm64_test.go",
+			wantDirective: ` gen -output-file-prefix "x_" -os "linux" -arch "arm64"`,
+		},
+		{
+			opts:          GenerateOptions{OutputFile: "wiring_gen.go", GOOS: "windows"},
+			want:          "wiring_gen_windows.go",
+			wantTest:      "wiring_gen_windows_test.go",
+			wantDirective: ` gen -output "wiring_gen.go" -os "windows"`,
+		},
+		{
+			opts:    GenerateOptions{OutputFile: "sub/wiring_gen.go"},
+			wantErr: true,
+		},
+		{
+			opts:    GenerateOptions{OutputFile: "wiring_gen"},
+			wantErr: true,
+		},
+		{
+			opts:    GenerateOptions{OutputFile: "wiring_test.go"},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		got, gotTest, err := test.opts.outputFiles()
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("%+v: outputFiles() error = %v", test.opts, err)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("%+v: outputFiles() = %q, %q; want error", test.opts, got, gotTest)
+			continue
+		}
+		if got != test.want || gotTest != test.wantTest {
+			t.Errorf("%+v: outputFiles() = %q, %q; want %q, %q", test.opts, got, gotTest, test.want, test.wantTest)
+		}
+		if got := test.opts.generateDirective(); got != test.wantDirective {
+			t.Errorf("%+v: generateDirective() = %q; want %q", test.opts, got, test.wantDirective)
+		}
+	}
+}
+
+func TestGenerateCache(t *testing.T) {
+	wireGo, err := ioutil.ReadFile(filepath.Join("..", "..", "autowire.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	test := &testCase{goFiles: map[string][]byte{
+		"github.com/dabbertorres/autowire/autowire.go": wireGo,
+		"example.com/foo/foo.go": []byte(`package main
+
+func main() { println(injectMessage()) }
+`),
+		"example.com/foo/wire.go": []byte(`//go:build wireinject
+
+package main
+
+import (
+	"example.com/bar"
+	"github.com/dabbertorres/autowire"
+)
+
+func injectMessage() string {
+	autowire.Build(bar.Set)
+	return ""
+}
+`),
+		"example.com/bar/bar.go": []byte(`package bar
+
+import "github.com/dabbertorres/autowire"
+
+var Set = autowire.NewSet(ProvideMessage)
+
+func ProvideMessage() string { return "Hello, World!" }
+`),
+	}}
+	gopath, err := ioutil.TempDir("", "autowire_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(gopath)
+	gopath, err = filepath.EvalSymlinks(gopath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := test.materialize(gopath); err != nil {
+		t.Fatal(err)
+	}
+	wd := filepath.Join(gopath, "src", "example.com")
+	env := append(os.Environ(), "GOPATH="+gopath)
+	opts := &GenerateOptions{CacheDir: filepath.Join(gopath, "cache")}
+	outputPath := filepath.Join(wd, "foo", "autowire_gen.go")
+
+	// generate runs Generate and returns its only result, committing it.
+	generate := func(step string) GenerateResult {
+		t.Helper()
+		gens, errs := Generate(context.Background(), wd, env, []string{"example.com/foo"}, opts)
+		if len(errs) > 0 {
+			t.Fatalf("%s: Generate: %v", step, errs)
+		}
+		if len(gens) != 1 {
+			t.Fatalf("%s: got %d results, want 1", step, len(gens))
+		}
+		gen := gens[0]
+		if len(gen.Errs) > 0 {
+			t.Fatalf("%s: Generate: %v", step, gen.Errs)
+		}
+		if gen.OutputPath != outputPath {
+			t.Errorf("%s: OutputPath = %q; want %q", step, gen.OutputPath, outputPath)
+		}
+		if err := gen.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		return gen
+	}
+	wantGenerated := func(step string, gen GenerateResult, want string) {
+		t.Helper()
+		if gen.Unchanged || !strings.Contains(string(gen.Content), want) {
+			t.Errorf("%s: Unchanged = %t, Content = %q; want generated content containing %q", step, gen.Unchanged, gen.Content, want)
+		}
+	}
+	wantUnchanged := func(step string, gen GenerateResult) {
+		t.Helper()
+		if !gen.Unchanged || len(gen.Content) > 0 {
+			t.Errorf("%s: Unchanged = %t, Content = %q; want an unchanged result", step, gen.Unchanged, gen.Content)
+		}
+	}
+
+	wantGenerated("first run", generate("first run"), "bar.ProvideMessage()")
+	wantUnchanged("second run", generate("second run"))
+
+	// Changing the signature of a provider in a dependency must invalidate
+	// the injector that uses it.
+	barGo := filepath.Join(wd, "bar", "bar.go")
+	newBar := `package bar
+
+import "github.com/dabbertorres/autowire"
+
+var Set = autowire.NewSet(ProvideMessage, ProvideCount)
+
+func ProvideCount() int { return 2 }
+
+func ProvideMessage(n int) string { return "Hello, World!" }
+`
+	if err := ioutil.WriteFile(barGo, []byte(newBar), 0666); err != nil {
+		t.Fatal(err)
+	}
+	wantGenerated("after changing a dependency", generate("after changing a dependency"), "bar.ProvideMessage(int2)")
+	wantUnchanged("after regenerating", generate("after regenerating"))
+
+	// Editing the generated file must regenerate it.
+	if err := ioutil.WriteFile(outputPath, []byte("package main\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	wantGenerated("after editing the output", generate("after editing the output"), "bar.ProvideMessage(int2)")
+
+	// Other options must not reuse the cached result.
+	opts.Header = []byte("// Header\n")
+	wantGenerated("after changing options", generate("after changing options"), "// Header")
+
+	// Neither must another environment, which may select other files.
+	env = append(env, "GOFLAGS=-tags=extra", "CGO_ENABLED=0")
+	wantGenerated("after changing the environment", generate("after changing the environment"), "// Header")
+	wantUnchanged("after regenerating in the environment", generate("after regenerating in the environment"))
+}
+
+func TestGenerateInto(t *testing.T) {
+	wireGo, err := ioutil.ReadFile(filepath.Join("..", "..", "autowire.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	test := &testCase{goFiles: map[string][]byte{
+		"github.com/dabbertorres/autowire/autowire.go": wireGo,
+		"example.com/foo/foo.go": []byte(`package main
+
+func main() { println(InjectMessage()) }
+`),
+		"example.com/bar/wire.go": []byte(`//go:build wireinject
+
+package bar
+
+import "github.com/dabbertorres/autowire"
+
+func InjectMessage() string {
+	panic(autowire.Build(Set, autowire.Into("example.com/foo")))
+}
+`),
+		"example.com/bar/bar.go": []byte(`package bar
+
+import "github.com/dabbertorres/autowire"
+
+var Set = autowire.NewSet(ProvideMessage)
+
+func ProvideMessage() string { return "Hello, World!" }
+`),
+	}}
+	gopath, err := ioutil.TempDir("", "autowire_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(gopath)
+	gopath, err = filepath.EvalSymlinks(gopath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := test.materialize(gopath); err != nil {
+		t.Fatal(err)
+	}
+	wd := filepath.Join(gopath, "src", "example.com")
+	env := append(os.Environ(), "GOPATH="+gopath)
+	outputPath := filepath.Join(wd, "foo", "autowire_gen.go")
+
+	gens, errs := Generate(context.Background(), wd, env, []string{"example.com/bar"}, &GenerateOptions{})
+	if len(errs) > 0 {
+		t.Fatal("Generate:", errs)
+	}
+	var gen *GenerateResult
+	for i := range gens {
+		if len(gens[i].Errs) > 0 {
+			t.Fatalf("%s: %v", gens[i].PkgPath, gens[i].Errs)
+		}
+		if gens[i].PkgPath == "example.com/foo" {
+			gen = &gens[i]
+		} else if len(gens[i].Content) > 0 {
+			t.Errorf("%s: got content, want none since its only injector is generated into example.com/foo:\n%s", gens[i].PkgPath, gens[i].Content)
+		}
+	}
+	if gen == nil {
+		t.Fatal("no result for example.com/foo")
+	}
+	if gen.OutputPath != outputPath {
+		t.Errorf("OutputPath = %q; want %q", gen.OutputPath, outputPath)
+	}
+	for _, want := range []string{"package main", "func InjectMessage() string {", "bar.ProvideMessage()"} {
+		if !strings.Contains(string(gen.Content), want) {
+			t.Errorf("Content = %q; want it to contain %q", gen.Content, want)
+		}
+	}
+	if strings.Contains(string(gen.Content), "wireinject") {
+		t.Errorf("Content = %q; want no wireinject build constraint", gen.Content)
+	}
+
+	// The names declared by the destination are not part of the cache key of
+	// the source package, but must still invalidate the cached output.
+	opts := &GenerateOptions{CacheDir: filepath.Join(gopath, "cache")}
+	generateDest := func(step string) GenerateResult {
+		t.Helper()
+		gens, errs := Generate(context.Background(), wd, env, []string{"example.com/bar"}, opts)
+		if len(errs) > 0 {
+			t.Fatalf("%s: Generate: %v", step, errs)
+		}
+		for _, gen := range gens {
+			if len(gen.Errs) > 0 {
+				t.Fatalf("%s: %s: %v", step, gen.PkgPath, gen.Errs)
+			}
+			if gen.PkgPath == "example.com/foo" {
+				if err := gen.Commit(); err != nil {
+					t.Fatal(err)
+				}
+				return gen
+			}
+		}
+		t.Fatalf("%s: no result for example.com/foo", step)
+		return GenerateResult{}
+	}
+	generateDest("first cached run")
+	if gen := generateDest("second cached run"); !gen.Unchanged {
+		t.Errorf("second cached run: Unchanged = false, Content = %q; want an unchanged result", gen.Content)
+	}
+	namesGo := filepath.Join(wd, "foo", "names.go")
+	if err := ioutil.WriteFile(namesGo, []byte("package main\n\nvar bar = 0\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if gen := generateDest("after declaring a colliding name"); gen.Unchanged || !strings.Contains(string(gen.Content), "bar2.ProvideMessage()") {
+		t.Errorf("after declaring a colliding name: Unchanged = %t, Content = %q; want generated content containing %q", gen.Unchanged, gen.Content, "bar2.ProvideMessage()")
+	}
+	if err := os.Remove(namesGo); err != nil {
+		t.Fatal(err)
+	}
+
+	// Providers that are not exported can't be called from the destination.
+	barGo := filepath.Join(wd, "bar", "bar.go")
+	newBar := `package bar
+
+import "github.com/dabbertorres/autowire"
+
+var Set = autowire.NewSet(provideMessage)
+
+func provideMessage() string { return "Hello, World!" }
+`
+	if err := ioutil.WriteFile(barGo, []byte(newBar), 0666); err != nil {
+		t.Fatal(err)
+	}
+	gens, errs = Generate(context.Background(), wd, env, []string{"example.com/bar"}, &GenerateOptions{})
+	if len(errs) > 0 {
+		t.Fatal("Generate:", errs)
+	}
+	const wantErr = "provider example.com/bar.provideMessage is not exported, so package example.com/foo can't call it"
+	found := false
+	for _, gen := range gens {
+		for _, err := range gen.Errs {
+			found = found || strings.Contains(err.Error(), wantErr)
+		}
+	}
+	if !found {
+		t.Errorf("Generate results = %+v; want an error containing %q", gens, wantErr)
+	}
+}
+
+func TestGeneratePlatform(t *testing.T) {
+	wireGo, err := ioutil.ReadFile(filepath.Join("..", "..", "autowire.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	test := &testCase{goFiles: map[string][]byte{
+		"github.com/dabbertorres/autowire/autowire.go": wireGo,
+		"example.com/foo/foo.go": []byte(`package main
+
+func main() { println(injectMessage()) }
+`),
+		"example.com/foo/wire.go": []byte(`//go:build wireinject
+
+package main
+
+import "github.com/dabbertorres/autowire"
+
+func injectMessage() string {
+	autowire.Build(Set)
+	return ""
+}
+`),
+		"example.com/foo/provider_linux.go": []byte(`package main
+
+import "github.com/dabbertorres/autowire"
+
+var Set = autowire.NewSet(provideMessage)
+
+func provideMessage() string { return "Hello, Linux!" }
+`),
+		"example.com/foo/provider_windows.go": []byte(`package main
+
+import "github.com/dabbertorres/autowire"
+
+type Separator rune
+
+var Set = autowire.NewSet(provideMessage, provideSeparator)
+
+func provideSeparator() Separator { return '\\' }
+
+func provideMessage(sep Separator) string { return "Hello," + string(sep) + "Windows!" }
+`),
+	}}
+	gopath, err := ioutil.TempDir("", "autowire_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(gopath)
+	gopath, err = filepath.EvalSymlinks(gopath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := test.materialize(gopath); err != nil {
+		t.Fatal(err)
+	}
+	wd := filepath.Join(gopath, "src", "example.com")
+	env := append(os.Environ(), "GOPATH="+gopath)
+
+	tests := []struct {
+		goos       string
+		outputFile string
+		want       string
+	}{
+		{goos: "linux", outputFile: "autowire_gen_linux.go", want: "provideMessage()"},
+		{goos: "windows", outputFile: "autowire_gen_windows.go", want: "provideMessage(separator)"},
+	}
+	for _, test := range tests {
+		opts := &GenerateOptions{GOOS: test.goos}
+		gens, errs := Generate(context.Background(), wd, env, []string{"example.com/foo"}, opts)
+		if len(errs) > 0 {
+			t.Fatalf("%s: Generate: %v", test.goos, errs)
+		}
+		if len(gens) != 1 {
+			t.Fatalf("%s: got %d results, want 1", test.goos, len(gens))
+		}
+		gen := gens[0]
+		if len(gen.Errs) > 0 {
+			t.Fatalf("%s: Generate: %v", test.goos, gen.Errs)
+		}
+		if want := filepath.Join(wd, "foo", test.outputFile); gen.OutputPath != want {
+			t.Errorf("%s: OutputPath = %q; want %q", test.goos, gen.OutputPath, want)
+		}
+		if !strings.Contains(string(gen.Content), test.want) {
+			t.Errorf("%s: Content = %q; want it to contain %q", test.goos, gen.Content, test.want)
+		}
+	}
+}
+
+// TestGenerateConcurrentInjectors solves injectors that share imported
+// provider sets on several goroutines, so that running the tests with -race
+// catches them sharing state even on a machine with a single CPU.
+func TestGenerateConcurrentInjectors(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	wireGo, err := ioutil.ReadFile(filepath.Join("..", "..", "autowire.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wire := new(bytes.Buffer)
+	wire.WriteString(`//go:build wireinject
+
+package main
+
+import (
+	"example.com/bar"
+	"github.com/dabbertorres/autowire"
+)
+`)
+	const n = 8
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(wire, `
+func inject%d() *bar.Service {
+	panic(autowire.Build(bar.Set))
+}
+`, i)
+	}
+	test := &testCase{goFiles: map[string][]byte{
+		"github.com/dabbertorres/autowire/autowire.go": wireGo,
+		"example.com/foo/foo.go": []byte(`package main
+
+func main() { println(inject0()) }
+`),
+		"example.com/foo/wire.go": wire.Bytes(),
+		"example.com/bar/bar.go": []byte(`package bar
+
+import "github.com/dabbertorres/autowire"
+
+type Config struct{}
+type Logger struct{}
+type Service struct{}
+
+func NewConfig() *Config                       { return new(Config) }
+func NewLogger(*Config) *Logger                { return new(Logger) }
+func NewService(*Config, *Logger) *Service     { return new(Service) }
+
+var ConfigSet = autowire.NewSet(NewConfig)
+var LogSet = autowire.NewSet(ConfigSet, NewLogger)
+var Set = autowire.NewSet(LogSet, NewService)
+`),
+	}}
+	gopath, err := ioutil.TempDir("", "autowire_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(gopath)
+	gopath, err = filepath.EvalSymlinks(gopath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := test.materialize(gopath); err != nil {
+		t.Fatal(err)
+	}
+	wd := filepath.Join(gopath, "src", "example.com")
+	env := append(os.Environ(), "GOPATH="+gopath)
+
+	gens, errs := Generate(context.Background(), wd, env, []string{"example.com/foo"}, &GenerateOptions{})
+	if len(errs) > 0 {
+		t.Fatalf("Generate: %v", errs)
+	}
+	if len(gens) != 1 {
+		t.Fatalf("got %d results, want 1", len(gens))
+	}
+	if len(gens[0].Errs) > 0 {
+		t.Fatalf("Generate: %v", gens[0].Errs)
+	}
+	for i := 0; i < n; i++ {
+		if want := fmt.Sprintf("func inject%d() *bar.Service {", i); !strings.Contains(string(gens[0].Content), want) {
+			t.Errorf("Content does not contain %q:\n%s", want, gens[0].Content)
+		}
+	}
+}
+
 func TestTypeVariableName(t *testing.T) {
 	var (
-		boolT           = types.Typ[types.Bool]
-		stringT         = types.Typ[types.String]
-		fooVarT         = types.NewNamed(types.NewTypeName(0, nil, "foo", stringT), stringT, nil)
-		nonameVarT      = types.NewNamed(types.NewTypeName(0, nil, "", stringT), stringT, nil)
-		barVarInFooPkgT = types.NewNamed(types.NewTypeName(0, types.NewPackage("my.example/foo", "foo"), "bar", stringT), stringT, nil)
+		boolT              = types.Typ[types.Bool]
+		stringT            = types.Typ[types.String]
+		fooVarT            = types.NewNamed(types.NewTypeName(0, nil, "foo", stringT), stringT, nil)
+		nonameVarT         = types.NewNamed(types.NewTypeName(0, nil, "", stringT), stringT, nil)
+		barVarInFooPkgT    = types.NewNamed(types.NewTypeName(0, types.NewPackage("my.example/foo", "foo"), "bar", stringT), stringT, nil)
+		barVarInNestedPkgT = types.NewNamed(types.NewTypeName(0, types.NewPackage("my.example/x/y/foo", "foo"), "bar", stringT), stringT, nil)
 	)
 	tests := []struct {
 		description     string
@@ -283,6 +868,9 @@ func TestTypeVariableName(t *testing.T) {
 		{"var in pkg type", barVarInFooPkgT, "", "", map[string]bool{}, "bar"},
 		{"var in pkg type with collision", barVarInFooPkgT, "", "", map[string]bool{"bar": true}, "fooBar"},
 		{"var in pkg type with double collision", barVarInFooPkgT, "", "", map[string]bool{"bar": true, "fooBar": true}, "bar2"},
+		{"var in nested pkg type with double collision", barVarInNestedPkgT, "", "", map[string]bool{"bar": true, "fooBar": true}, "yFooBar"},
+		{"var in nested pkg type with triple collision", barVarInNestedPkgT, "", "", map[string]bool{"bar": true, "fooBar": true, "yFooBar": true}, "xYFooBar"},
+		{"var in nested pkg type with all prefixes colliding", barVarInNestedPkgT, "", "", map[string]bool{"bar": true, "fooBar": true, "yFooBar": true, "xYFooBar": true}, "bar2"},
 	}
 	for _, test := range tests {
 		t.Run(fmt.Sprintf("%s: typeVariableName(%v, %q, %q, %v)", test.description, test.typ, test.defaultName, test.transformAppend, test.collides), func(t *testing.T) {
@@ -427,11 +1015,19 @@ type testCase struct {
 	name                 string
 	pkg                  string
 	header               []byte
+	maxFieldDepth        int
+	zeroFillBasics       bool
+	annotate             bool
 	goFiles              map[string][]byte
 	wantProgramOutput    []byte
 	wantWireOutput       []byte
+	wantWireTestOutput   []byte
+	wantDebug            bool
+	wantDebugOutput      []byte
 	wantWireError        bool
 	wantWireErrorStrings []string
+	wantWarnings         bool
+	wantWarningStrings   []string
 }
 
 // loadTestCase reads a test case from a directory.
@@ -444,6 +1040,17 @@ type testCase struct {
 //			file containing the package name containing the inject function
 //			(must also be package main)
 //
+//		max_field_depth
+//			file containing the GenerateOptions.MaxFieldDepth to generate
+//			with; optional
+//
+//		zero_fill_basics
+//			empty file whose presence sets GenerateOptions.ZeroFillBasics;
+//			optional
+//
+//		annotate
+//			empty file whose presence sets GenerateOptions.Annotate; optional
+//
 //		...
 //			any Go files found recursively placed under GOPATH/src/...
 //
@@ -460,10 +1067,24 @@ type testCase struct {
 //					verified output of autowire from a test run with
 //					-record, missing if autowire_errs.txt is present
 //
+//			autowire_gen_test.go
+//					verified output of autowire for injectors declared in
+//					_test.go files, missing if there are none
+//
 //			program_out.txt
 //					expected output from the final compiled program,
 //					missing if autowire_errs.txt is present
 //
+//			autowire_warnings.txt
+//					Expected warnings from a successful run of the Autowire
+//					Generate function, missing if no warnings expected.
+//					Formatted like autowire_errs.txt.
+//
+//			debug_out.txt
+//					expected dependency graphs written by the text
+//					GraphWriter, with paths scrubbed like errors;
+//					optional. To -record it, create an empty file.
+//
 func loadTestCase(root string, wireGoSrc []byte) (*testCase, error) {
 	name := filepath.Base(root)
 	pkg, err := ioutil.ReadFile(filepath.Join(root, "pkg"))
@@ -471,8 +1092,28 @@ func loadTestCase(root string, wireGoSrc []byte) (*testCase, error) {
 		return nil, fmt.Errorf("load test case %s: %v", name, err)
 	}
 	header, _ := ioutil.ReadFile(filepath.Join(root, "header"))
+	var maxFieldDepth int
+	if b, err := ioutil.ReadFile(filepath.Join(root, "max_field_depth")); err == nil {
+		maxFieldDepth, err = strconv.Atoi(string(bytes.TrimSpace(b)))
+		if err != nil {
+			return nil, fmt.Errorf("load test case %s: max_field_depth: %v", name, err)
+		}
+	}
+	_, err = os.Stat(filepath.Join(root, "zero_fill_basics"))
+	zeroFillBasics := err == nil
+	_, err = os.Stat(filepath.Join(root, "annotate"))
+	annotate := err == nil
 	var wantProgramOutput []byte
 	var wantWireOutput []byte
+	var wantWireTestOutput []byte
+	var wantDebug bool
+	var wantWarningStrings []string
+	warningsb, err := ioutil.ReadFile(filepath.Join(root, "want", "autowire_warnings.txt"))
+	wantWarnings := err == nil
+	if wantWarnings && len(warningsb) > 0 {
+		wantWarningStrings = strings.Split(string(warningsb), "\n\n")
+	}
+	var wantDebugOutput []byte
 	wireErrb, err := ioutil.ReadFile(filepath.Join(root, "want", "autowire_errs.txt"))
 	wantWireError := err == nil
 	var wantWireErrorStrings []string
@@ -484,7 +1125,10 @@ func loadTestCase(root string, wireGoSrc []byte) (*testCase, error) {
 			if err != nil {
 				return nil, fmt.Errorf("load test case %s: %v, if this is a new testcase, run with -record to generate the autowire_gen.go file", name, err)
 			}
+			wantWireTestOutput, _ = ioutil.ReadFile(filepath.Join(root, "want", "autowire_gen_test.go"))
 		}
+		wantDebugOutput, err = ioutil.ReadFile(filepath.Join(root, "want", "debug_out.txt"))
+		wantDebug = err == nil
 		wantProgramOutput, err = ioutil.ReadFile(filepath.Join(root, "want", "program_out.txt"))
 		if err != nil {
 			return nil, fmt.Errorf("load test case %s: %v", name, err)
@@ -522,11 +1166,19 @@ func loadTestCase(root string, wireGoSrc []byte) (*testCase, error) {
 		name:                 name,
 		pkg:                  string(bytes.TrimSpace(pkg)),
 		header:               header,
+		maxFieldDepth:        maxFieldDepth,
+		zeroFillBasics:       zeroFillBasics,
+		annotate:             annotate,
 		goFiles:              goFiles,
 		wantWireOutput:       wantWireOutput,
+		wantWireTestOutput:   wantWireTestOutput,
+		wantDebug:            wantDebug,
+		wantDebugOutput:      wantDebugOutput,
 		wantProgramOutput:    wantProgramOutput,
 		wantWireError:        wantWireError,
 		wantWireErrorStrings: wantWireErrorStrings,
+		wantWarnings:         wantWarnings,
+		wantWarningStrings:   wantWarningStrings,
 	}, nil
 }
 
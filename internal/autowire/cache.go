@@ -0,0 +1,363 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autowire
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// cacheVersion is written into every cache key, so that changing the layout
+// of cache entries invalidates the existing ones.
+const cacheVersion = "autowire cache v2"
+
+// cacheEnv lists the go environment variables that change how packages are
+// loaded, such as which files match their build constraints, and so are part
+// of the cache key.
+var cacheEnv = []string{
+	"GOFLAGS", "CGO_ENABLED", "GOOS", "GOARCH", "GOARM", "GO386", "GOAMD64",
+	"GOEXPERIMENT", "GO111MODULE", "GOPATH", "GOROOT", "GOWORK", "GOVERSION",
+}
+
+// genCache skips the packages whose inputs have not changed since they were
+// last generated. Packages are grouped by directory, since a package and its
+// test variants are written to the same directory and loaded together.
+type genCache struct {
+	dir string
+	// dirs lists the directories of the matched packages in load order.
+	dirs []string
+	keys map[string]string
+	hits map[string]*cacheEntry
+	// inputs lists the files hashed into the key of each directory, and sums
+	// holds their hashes, so that a file that changes while the directory is
+	// generated is not stored under the old key.
+	inputs map[string][]string
+	sums   map[string]string
+}
+
+// cacheEntry is the stored result of generating the packages in a directory.
+type cacheEntry struct {
+	Outputs []cacheOutput
+	// Usage holds the provider set members used by the directory's
+	// injectors, so that unused members are still reported correctly when
+	// the directory is skipped.
+	Usage []cachedSet
+}
+
+// cacheOutput is a GenerateResult without its content. Sum is the SHA-256 of
+// the content, or empty if no file was generated. DestSum is the dirSum of the
+// output's directory if it is the destination of autowire.Into, since that
+// package is not a dependency hashed into the key.
+type cacheOutput struct {
+	PkgPath    string
+	OutputPath string
+	Sum        string
+	DestSum    string `json:",omitempty"`
+}
+
+// cachedSet and cachedMember store a setMembers and its setMember values.
+type cachedSet struct {
+	PkgPath string
+	VarName string
+	Pos     token.Position
+	Members []cachedMember
+}
+
+type cachedMember struct {
+	Desc string
+	Pos  token.Position
+	Used bool
+}
+
+// openCache computes the cache key of each directory matched by patterns and
+// looks up the entries in dir. It only lists the packages and hashes their
+// files, which is much cheaper than type checking them. A nil cache is
+// returned if the inputs cannot be determined, in which case every package is
+// generated.
+func openCache(ctx context.Context, dir, wd string, env []string, patterns []string, opts *GenerateOptions) *genCache {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil
+	}
+	exeSum, err := fileSum(exe)
+	if err != nil {
+		return nil
+	}
+	goEnv, err := loadEnv(ctx, wd, env)
+	if err != nil {
+		return nil
+	}
+	cfg := &packages.Config{
+		Context:    ctx,
+		Mode:       packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps,
+		Dir:        wd,
+		Env:        env,
+		BuildFlags: buildFlags(opts.Tags),
+		Tests:      true,
+	}
+	pkgs, err := packages.Load(cfg, escapePatterns(patterns)...)
+	if err != nil {
+		return nil
+	}
+	c := &genCache{
+		dir:    dir,
+		keys:   make(map[string]string),
+		hits:   make(map[string]*cacheEntry),
+		inputs: make(map[string][]string),
+		sums:   make(map[string]string),
+	}
+	roots := make(map[string][]*packages.Package)
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil
+		}
+		if isTestMain(pkg) {
+			continue
+		}
+		outDir, err := detectOutputDir(pkg.GoFiles)
+		if err != nil {
+			return nil
+		}
+		if _, ok := roots[outDir]; !ok {
+			c.dirs = append(c.dirs, outDir)
+		}
+		roots[outDir] = append(roots[outDir], pkg)
+	}
+	for _, outDir := range c.dirs {
+		h := sha256.New()
+		fmt.Fprintf(h, "%s\n%s\n%s", cacheVersion, exeSum, goEnv)
+		fmt.Fprintf(h, "header %q\nprefix %q\noutput %q\ntags %q\nno-directive %t\nstrict %t\nmax-field-depth %d\nzero-fill-basics %t\nannotate %t\nos %q\narch %q\n",
+			opts.Header, opts.PrefixOutputFile, opts.OutputFile, opts.Tags, opts.NoAddGenerateDirective, opts.Strict, opts.MaxFieldDepth, opts.ZeroFillBasics, opts.Annotate, opts.GOOS, opts.GOARCH)
+		deps := make(map[string]*packages.Package)
+		for _, pkg := range roots[outDir] {
+			fmt.Fprintf(h, "root %s\n", pkg.ID)
+			packages.Visit([]*packages.Package{pkg}, nil, func(p *packages.Package) {
+				deps[p.ID] = p
+			})
+		}
+		ids := make([]string, 0, len(deps))
+		for id := range deps {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			pkg := deps[id]
+			fmt.Fprintf(h, "package %s %s %s\n", pkg.ID, pkg.PkgPath, pkg.Name)
+			for _, files := range [][]string{pkg.GoFiles, pkg.OtherFiles} {
+				for _, name := range files {
+					sum, ok := c.sums[name]
+					if !ok {
+						if sum, err = fileSum(name); err != nil {
+							return nil
+						}
+						c.sums[name] = sum
+					}
+					c.inputs[outDir] = append(c.inputs[outDir], name)
+					fmt.Fprintf(h, "file %s %s\n", name, sum)
+				}
+			}
+		}
+		key := hex.EncodeToString(h.Sum(nil))
+		c.keys[outDir] = key
+		if ent := c.lookup(key); ent != nil {
+			c.hits[outDir] = ent
+		}
+	}
+	return c
+}
+
+// loadEnv returns the variables in cacheEnv as the go command in wd sees them
+// with env, one "env NAME=value" line each. Asking the go command includes the
+// settings from go env -w and the defaults of the toolchain, which env alone
+// does not show.
+func loadEnv(ctx context.Context, wd string, env []string) (string, error) {
+	cmd := exec.CommandContext(ctx, "go", append([]string{"env"}, cacheEnv...)...)
+	cmd.Dir = wd
+	cmd.Env = env
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	values := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if len(values) != len(cacheEnv) {
+		return "", fmt.Errorf("go env printed %d values for %d variables", len(values), len(cacheEnv))
+	}
+	sb := new(strings.Builder)
+	for i, name := range cacheEnv {
+		fmt.Fprintf(sb, "env %s=%s\n", name, values[i])
+	}
+	return sb.String(), nil
+}
+
+// lookup returns the entry stored for key, if its outputs are still on disk
+// unmodified.
+func (c *genCache) lookup(key string) *cacheEntry {
+	data, err := ioutil.ReadFile(filepath.Join(c.dir, key+".json"))
+	if err != nil {
+		return nil
+	}
+	ent := new(cacheEntry)
+	if err := json.Unmarshal(data, ent); err != nil {
+		return nil
+	}
+	for _, out := range ent.Outputs {
+		if out.DestSum != "" {
+			if sum, err := dirSum(filepath.Dir(out.OutputPath), filepath.Base(out.OutputPath)); err != nil || sum != out.DestSum {
+				return nil
+			}
+		}
+		if out.Sum == "" {
+			continue
+		}
+		if sum, err := fileSum(out.OutputPath); err != nil || sum != out.Sum {
+			return nil
+		}
+	}
+	return ent
+}
+
+// hit returns the entry found for outDir, if any. c may be nil.
+func (c *genCache) hit(outDir string) *cacheEntry {
+	if c == nil {
+		return nil
+	}
+	return c.hits[outDir]
+}
+
+// misses returns the directories that must be generated.
+func (c *genCache) misses() []string {
+	var dirs []string
+	for _, outDir := range c.dirs {
+		if c.hits[outDir] == nil {
+			dirs = append(dirs, outDir)
+		}
+	}
+	return dirs
+}
+
+// store records the results generated for outDir. Results with errors are not
+// stored, and failing to write the entry only means that the directory is
+// generated again next time.
+func (c *genCache) store(outDir string, results []GenerateResult, usage *setUsage) {
+	key, ok := c.keys[outDir]
+	if !ok {
+		return
+	}
+	for _, name := range c.inputs[outDir] {
+		if sum, err := fileSum(name); err != nil || sum != c.sums[name] {
+			return
+		}
+	}
+	ent := &cacheEntry{Usage: usage.snapshot()}
+	for _, res := range results {
+		if len(res.Errs) > 0 {
+			return
+		}
+		out := cacheOutput{PkgPath: res.PkgPath, OutputPath: res.OutputPath, DestSum: res.destSum}
+		if len(res.Content) > 0 {
+			out.Sum = dataSum(res.Content)
+		}
+		ent.Outputs = append(ent.Outputs, out)
+	}
+	data, err := json.Marshal(ent)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0777); err != nil {
+		return
+	}
+	tmp, err := ioutil.TempFile(c.dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(c.dir, key+".json"))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// results returns the results of a skipped directory. They have no content,
+// since the generated files are already up to date.
+func (ent *cacheEntry) results() []GenerateResult {
+	results := make([]GenerateResult, 0, len(ent.Outputs))
+	for _, out := range ent.Outputs {
+		results = append(results, GenerateResult{
+			PkgPath:    out.PkgPath,
+			OutputPath: out.OutputPath,
+			Unchanged:  out.Sum != "",
+		})
+	}
+	return results
+}
+
+func fileSum(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// dirSum returns the SHA-256 of the names and contents of the Go files in dir,
+// except for test files and outputFile. Files excluded by build constraints
+// are included, which at worst regenerates the output needlessly.
+func dirSum(dir, outputFile string) (string, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, name := range names {
+		base := filepath.Base(name)
+		if base == outputFile || strings.HasSuffix(base, "_test.go") {
+			continue
+		}
+		sum, err := fileSum(name)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "file %s %s\n", base, sum)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func dataSum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
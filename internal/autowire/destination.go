@@ -0,0 +1,346 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autowire
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strconv"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// remoteInjectors are the injectors of a package that autowire.Into
+// generates into other packages.
+type remoteInjectors struct {
+	pkg       *packages.Package
+	outDir    string
+	injectors []*injector
+}
+
+// destination is a package that injectors are generated into.
+type destination struct {
+	// pkg has only the package's path, name and a scope holding its
+	// top-level names, since the package may not type check until the
+	// injectors are generated.
+	pkg *packages.Package
+	dir string
+	// sum is the dirSum of dir when the package was loaded.
+	sum string
+	// hasInjectors is true if the package declares injectors of its own.
+	hasInjectors bool
+	// from is the import path of the package whose injectors are generated
+	// into this one.
+	from string
+	err  error
+}
+
+// generateInto generates the remote injectors of each package into their
+// destination packages, and returns the results by the output directory of
+// the package that declares them. The destination packages are loaded
+// without type checking, and the output of each is only written by one
+// package.
+//
+// The output has no wireinject build constraint, so that the destination
+// package still type checks when it is loaded to generate its own output.
+func generateInto(ctx context.Context, wd string, env []string, outputFile string, opts *GenerateOptions, remotes []remoteInjectors, dirUsage func(string) *setUsage) map[string][]GenerateResult {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, r := range remotes {
+		for _, inj := range r.injectors {
+			if !seen[inj.dest] {
+				seen[inj.dest] = true
+				paths = append(paths, inj.dest)
+			}
+		}
+	}
+	dests, loadErr := loadDestinations(ctx, wd, env, opts.Tags, outputFile, paths)
+	results := make(map[string][]GenerateResult)
+	for _, r := range remotes {
+		var order []string
+		gens := make(map[string]*gen)
+		// files holds the file of the last injector generated into each
+		// destination, to write a header before the first one of each file.
+		files := make(map[string]*ast.File)
+		outs := make(map[string]*GenerateResult)
+		for _, inj := range r.injectors {
+			res := outs[inj.dest]
+			if res == nil {
+				res = &GenerateResult{PkgPath: inj.dest}
+				outs[inj.dest] = res
+				order = append(order, inj.dest)
+			}
+			pos := r.pkg.Fset.Position(inj.fn.Pos())
+			name := inj.fn.Name.Name
+			d := dests[inj.dest]
+			var err error
+			switch {
+			case loadErr != nil:
+				err = loadErr
+			case d.err != nil:
+				err = d.err
+			case d.hasInjectors:
+				err = fmt.Errorf("package %s declares injectors of its own, so injectors from other packages can't be generated into it", inj.dest)
+			case d.from != "" && d.from != r.pkg.PkgPath:
+				err = fmt.Errorf("injectors from package %s are already generated into package %s; generate the injectors of only one package into it", d.from, inj.dest)
+			}
+			if err != nil {
+				res.Errs = append(res.Errs, notePosition(pos, fmt.Errorf("inject %s: autowire.Into: %v", name, err)))
+				continue
+			}
+			d.from = r.pkg.PkgPath
+			res.OutputPath = filepath.Join(d.dir, outputFile)
+			res.destSum = d.sum
+			g := gens[inj.dest]
+			if g == nil {
+				pkg := *d.pkg
+				pkg.Fset = r.pkg.Fset
+				g = newGen(&pkg)
+				g.from = r.pkg.PkgPath
+				g.graphs = opts.Graphs
+				g.annotate = opts.Annotate
+				g.usage = dirUsage(r.outDir)
+				gens[inj.dest] = g
+			}
+			if files[inj.dest] != inj.file {
+				g.p("// Injectors from %s in package %s:\n\n", filepath.Base(pos.Filename), r.pkg.PkgPath)
+				files[inj.dest] = inj.file
+				g.addFileImports(inj.file)
+			}
+			res.Errs = append(res.Errs, g.inject(inj)...)
+		}
+		for _, dest := range order {
+			res := outs[dest]
+			if len(res.Errs) == 0 {
+				gens[dest].format(res, opts)
+			}
+			results[r.outDir] = append(results[r.outDir], *res)
+		}
+	}
+	return results
+}
+
+// loadDestinations loads the names and files of the packages at paths, and
+// parses their files for the names they declare. The output files are
+// skipped, since they are built with the wireinject tag too.
+func loadDestinations(ctx context.Context, wd string, env []string, tags, outputFile string, paths []string) (map[string]*destination, error) {
+	cfg := &packages.Config{
+		Context:    ctx,
+		Mode:       packages.NeedName | packages.NeedFiles,
+		Dir:        wd,
+		Env:        env,
+		BuildFlags: buildFlags(tags),
+	}
+	pkgs, err := packages.Load(cfg, escapePatterns(paths)...)
+	if err != nil {
+		return nil, err
+	}
+	dests := make(map[string]*destination, len(paths))
+	for _, path := range paths {
+		dests[path] = &destination{err: fmt.Errorf("package %s not found", path)}
+	}
+	for _, p := range pkgs {
+		d, ok := dests[p.PkgPath]
+		if !ok {
+			continue
+		}
+		if len(p.Errors) > 0 {
+			d.err = p.Errors[0]
+			continue
+		}
+		dir, err := detectOutputDir(p.GoFiles)
+		if err != nil {
+			d.err = err
+			continue
+		}
+		// The sum is taken before the files are parsed, so that a file
+		// changed meanwhile does not match the cached output.
+		sum, err := dirSum(dir, outputFile)
+		if err != nil {
+			d.err = err
+			continue
+		}
+		tpkg := types.NewPackage(p.PkgPath, p.Name)
+		fset := token.NewFileSet()
+		d.err = nil
+		for _, name := range p.GoFiles {
+			if filepath.Base(name) == outputFile {
+				continue
+			}
+			f, err := parser.ParseFile(fset, name, nil, 0)
+			if err != nil {
+				d.err = err
+				break
+			}
+			declareTopLevel(tpkg, f)
+			if declaresInjectors(f) {
+				d.hasInjectors = true
+			}
+		}
+		d.pkg = &packages.Package{ID: p.ID, PkgPath: p.PkgPath, Name: p.Name, Types: tpkg}
+		d.dir = dir
+		d.sum = sum
+	}
+	return dests, nil
+}
+
+// declareTopLevel adds a placeholder object to pkg's scope for each top-level
+// name that f declares, so that the generated code does not reuse them.
+func declareTopLevel(pkg *types.Package, f *ast.File) {
+	declare := func(id *ast.Ident) {
+		if id.Name != "_" && id.Name != "init" {
+			pkg.Scope().Insert(types.NewVar(id.Pos(), pkg, id.Name, types.Typ[types.Invalid]))
+		}
+	}
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				declare(decl.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					for _, id := range spec.Names {
+						declare(id)
+					}
+				case *ast.TypeSpec:
+					declare(spec.Name)
+				}
+			}
+		}
+	}
+}
+
+// declaresInjectors reports whether f calls autowire.Build, without type
+// checking it.
+func declaresInjectors(f *ast.File) bool {
+	var names []string
+	for _, impt := range f.Imports {
+		path, err := strconv.Unquote(impt.Path.Value)
+		if err != nil || !isWireImport(path) {
+			continue
+		}
+		if impt.Name != nil {
+			names = append(names, impt.Name.Name)
+		} else {
+			names = append(names, "autowire")
+		}
+	}
+	found := false
+	ast.Inspect(f, func(node ast.Node) bool {
+		sel, ok := node.(*ast.SelectorExpr)
+		if !ok || found {
+			return !found
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && sel.Sel.Name == "Build" {
+			for _, name := range names {
+				found = found || x.Name == name
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// accessibleCall reports an error if the code generated for c in the package
+// at pkgPath would refer to an unexported identifier of another package.
+func accessibleCall(c *call, pkgPath string) error {
+	switch c.kind {
+	case funcProviderCall:
+		if c.pkg.Path() != pkgPath && !ast.IsExported(c.name) {
+			if c.method {
+				return fmt.Errorf("method provider %s from package %s is not exported, so package %s can't call it", c.name, c.pkg.Path(), pkgPath)
+			}
+			return fmt.Errorf("provider %s.%s is not exported, so package %s can't call it", c.pkg.Path(), c.name, pkgPath)
+		}
+	case setterCall:
+		if c.pkg.Path() != pkgPath && !ast.IsExported(c.name) {
+			return fmt.Errorf("setter %s from package %s is not exported, so package %s can't call it", c.name, c.pkg.Path(), pkgPath)
+		}
+	case hookCall:
+		if c.pkg.Path() != pkgPath && !ast.IsExported(c.name) {
+			return fmt.Errorf("hook %s from package %s is not exported, so package %s can't call it", c.name, c.pkg.Path(), pkgPath)
+		}
+	case structProvider:
+		if c.pkg.Path() != pkgPath {
+			if !ast.IsExported(c.name) {
+				return fmt.Errorf("struct %s.%s is not exported, so package %s can't construct it", c.pkg.Path(), c.name, pkgPath)
+			}
+			for _, f := range c.fieldNames {
+				if !ast.IsExported(f) {
+					return fmt.Errorf("field %s of %s.%s is not exported, so package %s can't set it", f, c.pkg.Path(), c.name, pkgPath)
+				}
+			}
+		}
+	case selectorExpr:
+		if c.pkg != nil && c.pkg.Path() != pkgPath && !ast.IsExported(c.name) {
+			return fmt.Errorf("field %s from package %s is not exported, so package %s can't use it", c.name, c.pkg.Path(), pkgPath)
+		}
+	case sliceLiteral, nilValue:
+		if err := accessibleType(c.out, pkgPath); err != nil {
+			return err
+		}
+	}
+	for _, t := range c.typeArgs {
+		if err := accessibleType(t, pkgPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// accessibleType reports an error if t refers to a type that is not exported
+// from another package than the one at pkgPath.
+func accessibleType(t types.Type, pkgPath string) error {
+	switch t := t.(type) {
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() != pkgPath && !obj.Exported() {
+			return fmt.Errorf("type %s.%s is not exported, so package %s can't use it", obj.Pkg().Path(), obj.Name(), pkgPath)
+		}
+		return nil
+	case *types.Pointer:
+		return accessibleType(t.Elem(), pkgPath)
+	case *types.Slice:
+		return accessibleType(t.Elem(), pkgPath)
+	case *types.Array:
+		return accessibleType(t.Elem(), pkgPath)
+	case *types.Chan:
+		return accessibleType(t.Elem(), pkgPath)
+	case *types.Map:
+		if err := accessibleType(t.Key(), pkgPath); err != nil {
+			return err
+		}
+		return accessibleType(t.Elem(), pkgPath)
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{t.Params(), t.Results()} {
+			for i := 0; i < tuple.Len(); i++ {
+				if err := accessibleType(tuple.At(i).Type(), pkgPath); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autowire
+
+import (
+	"fmt"
+	"go/types"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// A DOTWriter is a GraphWriter that writes dependency graphs in the Graphviz
+// DOT language. All the graphs it receives are written to a single digraph,
+// with a cluster for each injector. Close must be called after the last graph
+// to finish the digraph.
+type DOTWriter struct {
+	w        io.Writer
+	clusters int
+	err      error
+}
+
+// NewDOTWriter returns a DOTWriter that writes to w.
+func NewDOTWriter(w io.Writer) *DOTWriter {
+	return &DOTWriter{w: w}
+}
+
+// dotStyles are the node attributes for each kind of GraphNode.
+var dotStyles = map[string]string{
+	"argument":        "shape=ellipse, style=dashed",
+	"provider":        "shape=box",
+	"struct provider": "shape=box, style=rounded",
+	"lazy provider":   "shape=box, style=dashed",
+	"value":           "shape=note",
+	"field":           "shape=box, style=dotted",
+	"collect":         "shape=folder",
+	"optional":        "shape=plaintext",
+	"zero":            "shape=plaintext",
+	"late bind":       "shape=box, style=bold",
+	"hook":            "shape=cds",
+}
+
+// WriteGraph writes g as a cluster of the digraph. Each node is labeled with
+// its type and what provides it, and has an edge to each of its inputs.
+func (dw *DOTWriter) WriteGraph(g *InjectorGraph) error {
+	if dw.err != nil {
+		return dw.err
+	}
+	sb := new(strings.Builder)
+	if dw.clusters == 0 {
+		sb.WriteString("digraph autowire {\n")
+	}
+	c := dw.clusters
+	dw.clusters++
+	fmt.Fprintf(sb, "\tsubgraph cluster_%d {\n", c)
+	fmt.Fprintf(sb, "\t\tlabel=%s;\n", strconv.Quote(g.Injector))
+	for i, n := range g.Nodes {
+		label := typeString(n.Out, (*types.Package).Name) + "\n" + n.Kind + " " + n.Name
+		fmt.Fprintf(sb, "\t\tn%d_%d [label=%s, %s];\n", c, i, strconv.Quote(label), dotStyles[n.Kind])
+	}
+	for i, n := range g.Nodes {
+		for _, in := range n.Inputs {
+			fmt.Fprintf(sb, "\t\tn%d_%d -> n%d_%d;\n", c, i, c, in)
+		}
+	}
+	sb.WriteString("\t}\n")
+	_, dw.err = io.WriteString(dw.w, sb.String())
+	return dw.err
+}
+
+// Close finishes the digraph. It does not close the underlying writer.
+func (dw *DOTWriter) Close() error {
+	if dw.err != nil {
+		return dw.err
+	}
+	s := "}\n"
+	if dw.clusters == 0 {
+		s = "digraph autowire {\n}\n"
+	}
+	_, dw.err = io.WriteString(dw.w, s)
+	return dw.err
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autowire
+
+import (
+	"bytes"
+	"go/types"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDOTWriter(t *testing.T) {
+	pkg := types.NewPackage("example.com/foo", "foo")
+	named := func(name string) types.Type {
+		return types.NewNamed(types.NewTypeName(0, pkg, name, nil), types.NewStruct(nil, nil), nil)
+	}
+	config := types.NewPointer(named("Config"))
+	logger := types.NewPointer(named("Logger"))
+	server := named("Server")
+	graphs := []*InjectorGraph{
+		{
+			Injector: "injectServer",
+			Nodes: []GraphNode{
+				{Kind: "argument", Name: "cfg", Out: config},
+				{Kind: "field", Name: "Addr", Out: types.Typ[types.String], Inputs: []int{0}},
+				{Kind: "value", Name: "Retries(3)", Out: types.Typ[types.Int]},
+				{Kind: "provider", Name: "example.com/foo.NewLogger", Out: logger, Inputs: []int{1}},
+				{Kind: "struct provider", Name: "example.com/foo.Server", Out: server, Inputs: []int{1, 2, 3}},
+			},
+		},
+		{
+			Injector: "injectLogger",
+			Nodes: []GraphNode{
+				{Kind: "value", Name: `"localhost"`, Out: types.Typ[types.String]},
+				{Kind: "provider", Name: "example.com/foo.NewLogger", Out: logger, Inputs: []int{0}},
+			},
+		},
+	}
+	const want = `digraph autowire {
+	subgraph cluster_0 {
+		label="injectServer";
+		n0_0 [label="*foo.Config\nargument cfg", shape=ellipse, style=dashed];
+		n0_1 [label="string\nfield Addr", shape=box, style=dotted];
+		n0_2 [label="int\nvalue Retries(3)", shape=note];
+		n0_3 [label="*foo.Logger\nprovider example.com/foo.NewLogger", shape=box];
+		n0_4 [label="foo.Server\nstruct provider example.com/foo.Server", shape=box, style=rounded];
+		n0_1 -> n0_0;
+		n0_3 -> n0_1;
+		n0_4 -> n0_1;
+		n0_4 -> n0_2;
+		n0_4 -> n0_3;
+	}
+	subgraph cluster_1 {
+		label="injectLogger";
+		n1_0 [label="string\nvalue \"localhost\"", shape=note];
+		n1_1 [label="*foo.Logger\nprovider example.com/foo.NewLogger", shape=box];
+		n1_1 -> n1_0;
+	}
+}
+`
+	buf := new(bytes.Buffer)
+	dw := NewDOTWriter(buf)
+	for _, g := range graphs {
+		if err := dw.WriteGraph(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := dw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("DOT output differs (-want +got):\n%s", diff)
+	}
+
+	buf.Reset()
+	if err := NewDOTWriter(buf).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "digraph autowire {\n}\n"; got != want {
+		t.Errorf("DOT output with no graphs = %q; want %q", got, want)
+	}
+}
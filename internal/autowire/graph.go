@@ -0,0 +1,197 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autowire
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"strings"
+)
+
+// A GraphWriter receives the resolved dependency graph of each injector that
+// Generate generates.
+type GraphWriter interface {
+	WriteGraph(g *InjectorGraph) error
+}
+
+// An InjectorGraph is the resolved dependency graph of an injector.
+type InjectorGraph struct {
+	// Injector is the name of the injector function.
+	Injector string
+	// Pkg is the import path of the package that declares the injector.
+	Pkg string
+	// Pos is the position of the injector function.
+	Pos token.Position
+	// Nodes are the values in the graph. The injector's arguments come first,
+	// followed by the values the injector produces in the order they are
+	// produced. The last node is the injector's result, unless the result is
+	// one of its arguments or the injector calls the setters of late bindings
+	// or autowire.After hooks, which follow the result along with the values
+	// only they need. The calls of autowire.Before hooks come before every
+	// value that they do not need.
+	Nodes []GraphNode
+}
+
+// A GraphNode is a value in an injector's dependency graph.
+type GraphNode struct {
+	// Kind describes how the value is produced: "argument", "provider",
+	// "lazy provider", for a factory that calls the provider, "struct
+	// provider", "value", "field", "collect", "optional", for the nil
+	// passed for an optional input that nothing provides, "zero", for the
+	// zero value passed for a basic type that nothing provides, "late bind",
+	// for a setter call that passes its second input to its first, which is
+	// the value of the node, or "hook", for a call to an autowire.Before or
+	// autowire.After hook, whose value is the empty tuple.
+	Kind string
+	// Name identifies what produces the value: the argument's name, the
+	// provider's, setter's or hook's package-qualified name, the value expression, the
+	// field's name, "nil" or the zero value.
+	Name string
+	// Pkg is the import path of the package that declares the provider,
+	// struct or field that produces the value, or empty for other kinds.
+	Pkg string
+	// Pos is the position of what produces the value.
+	Pos token.Position
+	// Out is the type of the value.
+	Out types.Type
+	// Inputs are the indices in Nodes of the values that the value is
+	// produced from.
+	Inputs []int
+}
+
+// injectorGraph builds the graph of an injector from the calls that solve
+// returned for it.
+func injectorGraph(fset *token.FileSet, pkgPath, name string, pos token.Pos, params *types.Tuple, calls []call) *InjectorGraph {
+	g := &InjectorGraph{
+		Injector: name,
+		Pkg:      pkgPath,
+		Pos:      fset.Position(pos),
+		Nodes:    make([]GraphNode, 0, params.Len()+len(calls)),
+	}
+	for i := 0; i < params.Len(); i++ {
+		p := params.At(i)
+		name := p.Name()
+		if name == "" {
+			name = "_"
+		}
+		g.Nodes = append(g.Nodes, GraphNode{
+			Kind: "argument",
+			Name: name,
+			Pos:  fset.Position(p.Pos()),
+			Out:  p.Type(),
+		})
+	}
+	for _, c := range calls {
+		n := GraphNode{
+			Pos:    fset.Position(c.pos),
+			Out:    c.out,
+			Inputs: c.args,
+		}
+		switch c.kind {
+		case funcProviderCall:
+			n.Kind = "provider"
+			if c.lazy {
+				n.Kind = "lazy provider"
+			}
+			n.Name = c.pkg.Path() + "." + c.name
+			n.Pkg = c.pkg.Path()
+			if c.method {
+				n.Name = "(" + typeString(c.ins[0], nil) + ")." + c.name
+			}
+		case structProvider:
+			n.Kind = "struct provider"
+			n.Name = c.pkg.Path() + "." + c.name
+			n.Pkg = c.pkg.Path()
+		case valueExpr:
+			n.Kind = "value"
+			n.Name = types.ExprString(c.valueExpr)
+		case selectorExpr:
+			n.Kind = "field"
+			n.Name = c.name
+			if c.pkg != nil {
+				n.Pkg = c.pkg.Path()
+			}
+		case sliceLiteral:
+			n.Kind = "collect"
+			n.Name = "autowire." + c.name
+		case nilValue:
+			n.Kind = "optional"
+			n.Name = "nil"
+		case zeroFill:
+			n.Kind = "zero"
+			n.Name = zeroValue(c.out, nil)
+		case setterCall, hookCall:
+			n.Kind = "late bind"
+			if c.kind == hookCall {
+				n.Kind = "hook"
+			}
+			n.Name = c.pkg.Path() + "." + c.name
+			n.Pkg = c.pkg.Path()
+			if c.method {
+				n.Name = "(" + typeString(c.ins[0], nil) + ")." + c.name
+			}
+		default:
+			panic("unknown kind")
+		}
+		g.Nodes = append(g.Nodes, n)
+	}
+	return g
+}
+
+// NewTextGraphWriter returns a GraphWriter that writes a human-readable
+// listing of each graph to w.
+func NewTextGraphWriter(w io.Writer) GraphWriter {
+	return textGraphWriter{w: w}
+}
+
+type textGraphWriter struct {
+	w io.Writer
+}
+
+func (tw textGraphWriter) WriteGraph(g *InjectorGraph) error {
+	sb := new(strings.Builder)
+	fmt.Fprintf(sb, "injector %s (%v):\n", g.Injector, g.Pos)
+	for i, n := range g.Nodes {
+		fmt.Fprintf(sb, "\t[%d] %s: %s %s (%v)\n", i, typeString(n.Out, nil), n.Kind, n.Name, n.Pos)
+		if len(n.Inputs) > 0 {
+			sb.WriteString("\t\tinputs:")
+			for _, in := range n.Inputs {
+				fmt.Fprintf(sb, " [%d]", in)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(tw.w, sb.String())
+	return err
+}
+
+// MultiGraphWriter returns a GraphWriter that writes each graph to all of the
+// given writers, stopping at the first error.
+func MultiGraphWriter(ws ...GraphWriter) GraphWriter {
+	return multiGraphWriter(ws)
+}
+
+type multiGraphWriter []GraphWriter
+
+func (mw multiGraphWriter) WriteGraph(g *InjectorGraph) error {
+	for _, w := range mw {
+		if err := w.WriteGraph(g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"go/types"
 	"os"
@@ -51,6 +52,8 @@ func (p *providerSetSrc) description(fset *token.FileSet, typ types.Type) string
 		return fmt.Sprintf("%q ", s)
 	}
 	switch {
+	case p.Provider != nil && (p.Provider.IsCollect || p.Provider.IsOptions):
+		return fmt.Sprintf("autowire.%s (%s)", p.Provider.Name, fset.Position(p.Provider.Pos))
 	case p.Provider != nil:
 		kind := "provider"
 		if p.Provider.IsStruct {
@@ -67,7 +70,26 @@ func (p *providerSetSrc) description(fset *token.FileSet, typ types.Type) string
 		args := p.InjectorArg.Args
 		return fmt.Sprintf("argument %s to injector function %s (%s)", args.Tuple.At(p.InjectorArg.Index).Name(), args.Name, fset.Position(args.Pos))
 	case p.Field != nil:
-		return fmt.Sprintf("autowire.FieldsOf (%s)", fset.Position(p.Field.Pos))
+		return fmt.Sprintf("autowire.FieldsOf field %s of %s (%s)", p.Field.Name, typeString(p.Field.Parent, nil), fset.Position(p.Field.Pos))
+	}
+	panic("providerSetSrc with no fields set")
+}
+
+// pos returns the source position of p.
+func (p *providerSetSrc) pos() token.Pos {
+	switch {
+	case p.Provider != nil:
+		return p.Provider.Pos
+	case p.Binding != nil:
+		return p.Binding.Pos
+	case p.Value != nil:
+		return p.Value.Pos
+	case p.Import != nil:
+		return p.Import.Pos
+	case p.InjectorArg != nil:
+		return p.InjectorArg.Args.Pos
+	case p.Field != nil:
+		return p.Field.Pos
 	}
 	panic("providerSetSrc with no fields set")
 }
@@ -86,6 +108,34 @@ func (p *providerSetSrc) trace(fset *token.FileSet, typ types.Type) []string {
 	return retval
 }
 
+// provider returns the provider function that p ultimately refers to for typ,
+// following imported sets, or nil if typ does not come from a provider
+// function.
+func (p *providerSetSrc) provider(typ types.Type) *Provider {
+	if p.Import != nil {
+		if parent := p.Import.srcMap.At(typ); parent != nil {
+			return parent.(*providerSetSrc).provider(typ)
+		}
+		return nil
+	}
+	if p.Provider == nil || p.Provider.IsStruct || p.Provider.IsCollect || p.Provider.IsOptions {
+		return nil
+	}
+	return p.Provider
+}
+
+// field returns the field that p ultimately refers to for typ, following
+// imported sets, or nil if typ does not come from autowire.FieldsOf.
+func (p *providerSetSrc) field(typ types.Type) *Field {
+	if p.Import != nil {
+		if parent := p.Import.srcMap.At(typ); parent != nil {
+			return parent.(*providerSetSrc).field(typ)
+		}
+		return nil
+	}
+	return p.Field
+}
+
 // A ProviderSet describes a set of providers.  The zero value is an empty
 // ProviderSet.
 type ProviderSet struct {
@@ -98,11 +148,14 @@ type ProviderSet struct {
 	// variable.
 	VarName string
 
-	Providers []*Provider
-	Bindings  []*IfaceBinding
-	Values    []*Value
-	Fields    []*Field
-	Imports   []*ProviderSet
+	Providers    []*Provider
+	Bindings     []*IfaceBinding
+	Values       []*Value
+	Fields       []*Field
+	Defaults     []*DefaultProvider
+	LateBindings []*LateBinding
+	Hooks        []*Hook
+	Imports      []*ProviderSet
 	// InjectorArgs is only filled in for autowire.Build.
 	InjectorArgs *InjectorArgs
 
@@ -113,6 +166,20 @@ type ProviderSet struct {
 	// srcMap maps from provided type to a *providerSetSrc capturing the
 	// Provider, Binding, Value, or Import that provided the type.
 	srcMap *typeutil.Map
+
+	// defaultMap maps from interface type to the *DefaultProvider for it.
+	// It includes all of the imported defaults.
+	defaultMap *typeutil.Map
+
+	// overrides holds a warning for each provider of the set's imports that
+	// is replaced by a provider declared in a _test.go file, as allowed for
+	// sets declared in _test.go files.
+	overrides []error
+
+	// order lists the types provided by the set in the order they are listed
+	// in the call to autowire.NewSet or autowire.Build, with imported sets
+	// expanded in place. A type may be listed more than once.
+	order []types.Type
 }
 
 // Outputs returns a new slice containing the set of possible types the
@@ -130,6 +197,16 @@ func (set *ProviderSet) For(t types.Type) ProvidedType {
 	return *pt.(*ProvidedType)
 }
 
+// defaultFor returns the default provider for the interface type iface, or
+// nil if the set has none.
+func (set *ProviderSet) defaultFor(iface types.Type) *DefaultProvider {
+	if set.defaultMap == nil {
+		return nil
+	}
+	d, _ := set.defaultMap.At(iface).(*DefaultProvider)
+	return d
+}
+
 // An IfaceBinding declares that a type should be used to satisfy inputs
 // of the given interface type.
 type IfaceBinding struct {
@@ -143,6 +220,70 @@ type IfaceBinding struct {
 	Pos token.Pos
 }
 
+// A DefaultProvider declares a provider to fall back to for inputs of an
+// interface type that the provider set does not otherwise provide.
+type DefaultProvider struct {
+	// Iface is the interface type, which is what can be injected.
+	Iface types.Type
+
+	// Provider is a provider function whose output is assignable to Iface.
+	Provider *Provider
+
+	// Pos is the position of the call to autowire.Default.
+	Pos token.Pos
+}
+
+// A LateBinding declares a setter that passes an interface value to a value
+// after both have been built, so that the interface may depend on the value.
+type LateBinding struct {
+	// Pkg is the package that the setter resides in.
+	Pkg *types.Package
+
+	// Name is the name of the setter function or method.
+	Name string
+
+	// Method is true if the setter is a method expression, such as
+	// (*T).SetFoo, which is called on the target.
+	Method bool
+
+	// Target is the type of the setter's first parameter, which is the value
+	// that is set.
+	Target types.Type
+
+	// Iface is the interface type of the setter's second parameter.
+	Iface types.Type
+
+	// Pos is the position of the call to autowire.LateBind.
+	Pos token.Pos
+}
+
+// A Hook declares a function that an injector calls with values from the set
+// without providing a value, either before it builds anything else or just
+// before it returns.
+type Hook struct {
+	// Pkg is the package that the hook function resides in.
+	Pkg *types.Package
+
+	// Name is the name of the hook function or method.
+	Name string
+
+	// Method is true if the hook is a method expression, such as
+	// (*Config).Validate, which is called on its first argument.
+	Method bool
+
+	// After is true for autowire.After and false for autowire.Before.
+	After bool
+
+	// Args is the list of types the hook is called with.
+	Args []types.Type
+
+	// HasErr is true if the hook returns an error.
+	HasErr bool
+
+	// Pos is the position of the call to autowire.Before or autowire.After.
+	Pos token.Pos
+}
+
 // Provider records the signature of a provider. A provider is a
 // single Go object, either a function or a named struct type.
 type Provider struct {
@@ -166,6 +307,28 @@ type Provider struct {
 	// Otherwise it's a function.
 	IsStruct bool
 
+	// IsCollect is true if this provider is a call to autowire.Collect. It
+	// provides a slice of the types in the provider set that are assignable to
+	// the element type, and so has no Args of its own.
+	IsCollect bool
+
+	// IsOptions is true if this provider is the slice of options created by a
+	// call to autowire.Options. Its Args are the qualified types of the option
+	// providers, in the order they are listed.
+	IsOptions bool
+
+	// IsMethod is true if this provider is a method expression, such as
+	// (*Config).Database. Its first Arg is the receiver, and it is called as
+	// a method of the receiver.
+	IsMethod bool
+
+	// IsLazy is true if this provider was created by autowire.Lazy. Its only
+	// Out is the factory type, a function without parameters that returns what
+	// the provider function returns, and it is called by the factory instead
+	// of by the injector. HasCleanup and HasErr are then false, since creating
+	// the factory can't fail.
+	IsLazy bool
+
 	// Out is the set of types this provider produces. It will always
 	// contain at least one type.
 	Out []types.Type
@@ -177,6 +340,15 @@ type Provider struct {
 	// HasErr reports whether the provider function can return an error.
 	// (Always false for structs.)
 	HasErr bool
+
+	// TypeArgs is the list of type arguments a generic provider function is
+	// instantiated with. It is nil if the function is not generic.
+	TypeArgs []types.Type
+
+	// Qualifier is the name given to the provider by autowire.Named, if any.
+	// Out then holds the qualified type that stands for the provided type
+	// qualified with this name.
+	Qualifier string
 }
 
 // ProviderInput describes an incoming edge in the provider graph.
@@ -185,6 +357,26 @@ type ProviderInput struct {
 
 	// If the provider is a struct, FieldName will be the field name to set.
 	FieldName string
+
+	// TypeName is the name given by the field's `autowire:"Name"` tag, if the
+	// provider is a struct. If set, the field is filled in using the provider
+	// qualified with that name by autowire.Named or the provided type with that
+	// name, rather than the provider for Type.
+	TypeName string
+
+	// ParamName is the name of the parameter, if the provider is a function.
+	// It is only used to describe the input in errors.
+	ParamName string
+
+	// Qualifier is the name given to the parameter by autowire.Qualify, if
+	// the provider is a function. If set, the parameter is filled in using the
+	// provider of Type qualified with that name by autowire.Named rather than
+	// the provider for Type.
+	Qualifier string
+
+	// Optional is true if the input was marked optional by autowire.Optional.
+	// If nothing provides the input, nil is passed instead.
+	Optional bool
 }
 
 // Value describes a value expression.
@@ -234,7 +426,13 @@ type Field struct {
 	// Out is the field's provided types. The first element provides the
 	// field type. If the field is coming from a pointer to a struct,
 	// there will be a second element providing a pointer to the field.
+	// If the field was qualified by "Name=qualifier", these are the
+	// qualified types that stand for them.
 	Out []types.Type
+	// Wildcard is the position of the call to autowire.FieldsOf if the field
+	// was listed by "*", and token.NoPos otherwise. The fields listed by the
+	// same "*" count as used if any of them is.
+	Wildcard token.Pos
 }
 
 // Load finds all the provider sets in the packages that match the given
@@ -249,7 +447,7 @@ type Field struct {
 // In case of duplicate environment variables, the last one in the list
 // takes precedence.
 func Load(ctx context.Context, wd string, env []string, tags string, patterns []string) (*Info, []error) {
-	pkgs, errs := load(ctx, wd, env, tags, patterns)
+	pkgs, errs := load(ctx, wd, env, tags, patterns, false)
 	if len(errs) > 0 {
 		return nil, errs
 	}
@@ -319,7 +517,10 @@ func Load(ctx context.Context, wd string, env []string, tags string, patterns []
 					ec.add(notePositionAll(fset.Position(fn.Pos()), errs)...)
 					continue
 				}
-				_, errs = solve(fset, out.out, ins, set)
+				_, _, used, errs := solve(fset, out.out, ins, set, false)
+				if len(errs) == 0 {
+					errs = verifyArgsUsed(set, used)
+				}
 				if len(errs) > 0 {
 					ec.add(mapErrors(errs, func(e error) error {
 						if w, ok := e.(*wireErr); ok {
@@ -349,23 +550,19 @@ func Load(ctx context.Context, wd string, env []string, tags string, patterns []
 // env is nil or empty, it is interpreted as an empty set of variables.
 // In case of duplicate environment variables, the last one in the list
 // takes precedence.
-func load(ctx context.Context, wd string, env []string, tags string, patterns []string) ([]*packages.Package, []error) {
+//
+// If tests is true, the test variants of the packages are loaded as well.
+func load(ctx context.Context, wd string, env []string, tags string, patterns []string, tests bool) ([]*packages.Package, []error) {
 	cfg := &packages.Config{
 		Context:    ctx,
 		Mode:       packages.LoadAllSyntax,
 		Dir:        wd,
 		Env:        env,
-		BuildFlags: []string{"-tags=wireinject"},
+		BuildFlags: buildFlags(tags),
+		Tests:      tests,
 		// TODO(light): Use ParseFile to skip function bodies and comments in indirect packages.
 	}
-	if len(tags) > 0 {
-		cfg.BuildFlags[0] += " " + tags
-	}
-	escaped := make([]string, len(patterns))
-	for i := range patterns {
-		escaped[i] = "pattern=" + patterns[i]
-	}
-	pkgs, err := packages.Load(cfg, escaped...)
+	pkgs, err := packages.Load(cfg, escapePatterns(patterns)...)
 	if err != nil {
 		return nil, []error{err}
 	}
@@ -381,6 +578,24 @@ func load(ctx context.Context, wd string, env []string, tags string, patterns []
 	return pkgs, nil
 }
 
+// buildFlags returns the build flags that select the injector files, along
+// with any extra tags.
+func buildFlags(tags string) []string {
+	flags := []string{"-tags=wireinject"}
+	if len(tags) > 0 {
+		flags[0] += " " + tags
+	}
+	return flags
+}
+
+func escapePatterns(patterns []string) []string {
+	escaped := make([]string, len(patterns))
+	for i := range patterns {
+		escaped[i] = "pattern=" + patterns[i]
+	}
+	return escaped
+}
+
 // Info holds the result of Load.
 type Info struct {
 	Fset *token.FileSet
@@ -421,6 +636,42 @@ type objectCache struct {
 	packages map[string]*packages.Package
 	objects  map[objRef]objCacheEntry
 	hasher   typeutil.Hasher
+
+	// named caches the providers created by autowire.Named, so that the same
+	// provider qualified with the same name is only created once.
+	named map[namedRef]*Provider
+	// optional caches the providers created by autowire.Optional in the same
+	// way.
+	optional map[optionalRef]*Provider
+	// lazy maps the providers passed to autowire.Lazy to the lazy providers
+	// created for them.
+	lazy map[*Provider]*Provider
+	// qualify caches the providers created by autowire.Qualify for functions
+	// that are not generic.
+	qualify map[qualifyRef]*Provider
+	// maxFieldDepth limits how deeply nested in embedded structs the fields
+	// named by autowire.FieldsOf may be. Zero means no limit.
+	maxFieldDepth int
+	// qualified maps each qualifier to a map from types to the qualified
+	// types created by qualifiedType.
+	qualified map[string]*typeutil.Map
+}
+
+type namedRef struct {
+	provider *Provider
+	name     string
+}
+
+type qualifyRef struct {
+	fn *types.Func
+	// names holds the names given to the parameters, in order.
+	names string
+}
+
+type optionalRef struct {
+	provider *Provider
+	// inputs holds the indexes of the optional inputs, in order.
+	inputs string
 }
 
 type objRef struct {
@@ -442,6 +693,12 @@ func newObjectCache(pkgs []*packages.Package) *objectCache {
 		packages: make(map[string]*packages.Package),
 		objects:  make(map[objRef]objCacheEntry),
 		hasher:   typeutil.MakeHasher(),
+
+		named:     make(map[namedRef]*Provider),
+		optional:  make(map[optionalRef]*Provider),
+		lazy:      make(map[*Provider]*Provider),
+		qualify:   make(map[qualifyRef]*Provider),
+		qualified: make(map[string]*typeutil.Map),
 	}
 	// Depth-first search of all dependencies to gather import path to
 	// packages.Package mapping. go/packages guarantees that for a single
@@ -520,10 +777,27 @@ func (oc *objectCache) varDecl(obj *types.Var) *ast.ValueSpec {
 }
 
 // processExpr converts an expression into a Wire structure. It may return a
-// *Provider, an *IfaceBinding, a *ProviderSet, a *Value or a []*Field.
+// *Provider, an *IfaceBinding, a *ProviderSet, a *Value, a []*Field, a
+// *DefaultProvider, a *LateBinding or a *Hook.
 func (oc *objectCache) processExpr(info *types.Info, pkgPath string, expr ast.Expr, varName string) (interface{}, []error) {
 	exprPos := oc.fset.Position(expr.Pos())
 	expr = astutil.Unparen(expr)
+	if fn, typeArgs := funcInstance(info, expr); fn != nil {
+		// Instances are not cached, since the same function may be
+		// instantiated with different type arguments.
+		p, errs := processFuncInstance(oc.fset, fn, info.TypeOf(expr).(*types.Signature), typeArgs, nil)
+		return p, mapErrors(errs, func(err error) error {
+			return notePosition(exprPos, err)
+		})
+	}
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		if s := info.Selections[sel]; s != nil && s.Kind() == types.MethodExpr {
+			p, errs := processMethodProvider(oc.fset, s.Obj().(*types.Func), info.TypeOf(expr).(*types.Signature), nil)
+			return p, mapErrors(errs, func(err error) error {
+				return notePosition(exprPos, err)
+			})
+		}
+	}
 	if obj := qualifiedIdentObject(info, expr); obj != nil {
 		item, errs := oc.get(obj)
 		return item, mapErrors(errs, func(err error) error {
@@ -552,6 +826,12 @@ func (oc *objectCache) processExpr(info *types.Info, pkgPath string, expr ast.Ex
 				return nil, []error{notePosition(exprPos, err)}
 			}
 			return b, nil
+		case "BindAll":
+			b, err := processBindAll(oc.fset, info, call)
+			if err != nil {
+				return nil, []error{notePosition(exprPos, err)}
+			}
+			return b, nil
 		case "Value":
 			v, err := processValue(oc.fset, info, call)
 			if err != nil {
@@ -571,11 +851,49 @@ func (oc *objectCache) processExpr(info *types.Info, pkgPath string, expr ast.Ex
 			}
 			return s, nil
 		case "FieldsOf":
-			v, err := processFieldsOf(oc.fset, info, call)
+			v, err := oc.processFieldsOf(info, pkgPath, call)
 			if err != nil {
 				return nil, []error{notePosition(exprPos, err)}
 			}
 			return v, nil
+		case "Named":
+			p, errs := oc.processNamed(info, pkgPath, call)
+			return p, notePositionAll(exprPos, errs)
+		case "Optional":
+			p, errs := oc.processOptional(info, pkgPath, call)
+			return p, notePositionAll(exprPos, errs)
+		case "Lazy":
+			p, errs := oc.processLazy(info, pkgPath, call)
+			return p, notePositionAll(exprPos, errs)
+		case "Qualify":
+			p, errs := oc.processQualify(info, call)
+			return p, notePositionAll(exprPos, errs)
+		case "Options":
+			set, errs := oc.processOptions(info, pkgPath, fnObj.Pkg(), call)
+			return set, notePositionAll(exprPos, errs)
+		case "Default":
+			d, errs := oc.processDefault(info, pkgPath, call)
+			return d, notePositionAll(exprPos, errs)
+		case "LateBind":
+			lb, err := processLateBind(oc.fset, info, call)
+			if err != nil {
+				return nil, []error{notePosition(exprPos, err)}
+			}
+			return lb, nil
+		case "Before", "After":
+			h, err := processHook(info, call, fnObj.Name())
+			if err != nil {
+				return nil, []error{notePosition(exprPos, err)}
+			}
+			return h, nil
+		case "Into":
+			return nil, []error{notePosition(exprPos, errors.New("autowire.Into may only be passed directly to autowire.Build"))}
+		case "Collect":
+			p, err := processCollect(oc.fset, info, fnObj.Pkg(), call)
+			if err != nil {
+				return nil, []error{notePosition(exprPos, err)}
+			}
+			return p, nil
 		default:
 			return nil, []error{notePosition(exprPos, errors.New("unknown pattern"))}
 		}
@@ -599,6 +917,11 @@ func (oc *objectCache) processNewSet(info *types.Info, pkgPath string, call *ast
 		PkgPath:      pkgPath,
 		VarName:      varName,
 	}
+	if args != nil {
+		for i := 0; i < args.Tuple.Len(); i++ {
+			pset.order = append(pset.order, args.Tuple.At(i).Type())
+		}
+	}
 	ec := new(errorCollector)
 	for _, arg := range call.Args {
 		item, errs := oc.processExpr(info, pkgPath, arg, "")
@@ -609,14 +932,32 @@ func (oc *objectCache) processNewSet(info *types.Info, pkgPath string, call *ast
 		switch item := item.(type) {
 		case *Provider:
 			pset.Providers = append(pset.Providers, item)
+			pset.order = append(pset.order, item.Out...)
 		case *ProviderSet:
 			pset.Imports = append(pset.Imports, item)
+			pset.order = append(pset.order, item.order...)
 		case *IfaceBinding:
 			pset.Bindings = append(pset.Bindings, item)
+			pset.order = append(pset.order, item.Iface)
+		case []*IfaceBinding:
+			pset.Bindings = append(pset.Bindings, item...)
+			for _, b := range item {
+				pset.order = append(pset.order, b.Iface)
+			}
 		case *Value:
 			pset.Values = append(pset.Values, item)
+			pset.order = append(pset.order, item.Out)
 		case []*Field:
 			pset.Fields = append(pset.Fields, item...)
+			for _, f := range item {
+				pset.order = append(pset.order, f.Out...)
+			}
+		case *DefaultProvider:
+			pset.Defaults = append(pset.Defaults, item)
+		case *LateBinding:
+			pset.LateBindings = append(pset.LateBindings, item)
+		case *Hook:
+			pset.Hooks = append(pset.Hooks, item)
 		default:
 			panic("unknown item type")
 		}
@@ -629,12 +970,41 @@ func (oc *objectCache) processNewSet(info *types.Info, pkgPath string, call *ast
 	if len(errs) > 0 {
 		return nil, errs
 	}
-	if errs := verifyAcyclic(pset.providerMap, oc.hasher); len(errs) > 0 {
+	pset.defaultMap, errs = buildDefaultMap(oc.fset, oc.hasher, pset)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if errs := verifyAcyclic(oc.fset, pset.providerMap, oc.hasher); len(errs) > 0 {
 		return nil, errs
 	}
 	return pset, nil
 }
 
+// funcInstance checks whether expr is an instantiation of a generic function,
+// such as pkg.NewFoo[T]. If so, it returns the function and the type
+// arguments it is instantiated with.
+func funcInstance(info *types.Info, expr ast.Expr) (*types.Func, []types.Type) {
+	var x ast.Expr
+	var indices []ast.Expr
+	switch expr := expr.(type) {
+	case *ast.IndexExpr:
+		x, indices = expr.X, []ast.Expr{expr.Index}
+	case *ast.IndexListExpr:
+		x, indices = expr.X, expr.Indices
+	default:
+		return nil, nil
+	}
+	fn, ok := qualifiedIdentObject(info, x).(*types.Func)
+	if !ok || fn.Type().(*types.Signature).TypeParams().Len() != len(indices) {
+		return nil, nil
+	}
+	typeArgs := make([]types.Type, len(indices))
+	for i, index := range indices {
+		typeArgs[i] = info.TypeOf(index)
+	}
+	return fn, typeArgs
+}
+
 // structArgType attempts to interpret an expression as a simple struct type.
 // It assumes any parentheses have been stripped.
 func structArgType(info *types.Info, expr ast.Expr) *types.TypeName {
@@ -674,7 +1044,14 @@ func qualifiedIdentObject(info *types.Info, expr ast.Expr) types.Object {
 
 // processFuncProvider creates a provider for a function declaration.
 func processFuncProvider(fset *token.FileSet, fn *types.Func) (*Provider, []error) {
-	sig := fn.Type().(*types.Signature)
+	return processFuncInstance(fset, fn, fn.Type().(*types.Signature), nil, nil)
+}
+
+// processFuncInstance creates a provider for the generic function fn
+// instantiated with typeArgs, whose signature is sig. If fn is not generic,
+// typeArgs is nil and sig is the signature of fn. qualifiers holds the names
+// given to the parameters by autowire.Qualify, or is nil.
+func processFuncInstance(fset *token.FileSet, fn *types.Func, sig *types.Signature, typeArgs []types.Type, qualifiers []string) (*Provider, []error) {
 	fpos := fn.Pos()
 	providerSig, err := funcOutput(sig)
 	if err != nil {
@@ -690,20 +1067,503 @@ func processFuncProvider(fset *token.FileSet, fn *types.Func) (*Provider, []erro
 		Out:        []types.Type{providerSig.out},
 		HasCleanup: providerSig.cleanup,
 		HasErr:     providerSig.err,
+		TypeArgs:   typeArgs,
 	}
 	for i := 0; i < params.Len(); i++ {
 		provider.Args[i] = ProviderInput{
-			Type: params.At(i).Type(),
+			Type:      params.At(i).Type(),
+			ParamName: params.At(i).Name(),
+		}
+		if qualifiers != nil {
+			provider.Args[i].Qualifier = qualifiers[i]
 		}
 		for j := 0; j < i; j++ {
-			if types.Identical(provider.Args[i].Type, provider.Args[j].Type) {
-				return nil, []error{notePosition(fset.Position(fpos), fmt.Errorf("provider has multiple parameters of type %s", types.TypeString(provider.Args[j].Type, nil)))}
+			if types.Identical(provider.Args[i].Type, provider.Args[j].Type) && provider.Args[i].Qualifier == provider.Args[j].Qualifier {
+				return nil, []error{notePosition(fset.Position(fpos), fmt.Errorf("provider has multiple parameters of type %s", typeString(provider.Args[j].Type, nil)))}
 			}
 		}
 	}
 	return provider, nil
 }
 
+// processMethodProvider creates a provider for a method expression of the
+// method fn, whose signature sig has the receiver as its first parameter.
+func processMethodProvider(fset *token.FileSet, fn *types.Func, sig *types.Signature, qualifiers []string) (*Provider, []error) {
+	p, errs := processFuncInstance(fset, fn, sig, nil, qualifiers)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	p.IsMethod = true
+	return p, nil
+}
+
+// processNamed creates a provider from a call to autowire.Named. It is a copy
+// of the given function provider whose output type is qualified by name.
+func (oc *objectCache) processNamed(info *types.Info, pkgPath string, call *ast.CallExpr) (*Provider, []error) {
+	// Assumes that call.Fun is autowire.Named.
+
+	if len(call.Args) != 2 {
+		return nil, []error{errors.New("call to Named takes exactly two arguments")}
+	}
+	tv := info.Types[call.Args[0]]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return nil, []error{errors.New("first argument to Named must be a string constant")}
+	}
+	name := constant.StringVal(tv.Value)
+	if !token.IsIdentifier(name) {
+		return nil, []error{fmt.Errorf("name %q given to Named is not a valid identifier", name)}
+	}
+	item, errs := oc.processExpr(info, pkgPath, call.Args[1], "")
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	p, ok := item.(*Provider)
+	if !ok || p.IsStruct || p.Qualifier != "" {
+		return nil, []error{errors.New("second argument to Named must be a provider function")}
+	}
+	return oc.qualifiedProvider(p, name), nil
+}
+
+// processQualify creates a provider from a call to autowire.Qualify. It is
+// the provider for the given function whose parameters are qualified by the
+// given names. The function is processed here rather than by processExpr,
+// since its parameters may only have distinct types once they are qualified.
+func (oc *objectCache) processQualify(info *types.Info, call *ast.CallExpr) (*Provider, []error) {
+	// Assumes that call.Fun is autowire.Qualify.
+
+	if len(call.Args) < 1 {
+		return nil, []error{errors.New("call to Qualify takes a provider function and a name for each of its parameters")}
+	}
+	names := make([]string, 0, len(call.Args)-1)
+	for _, arg := range call.Args[1:] {
+		tv := info.Types[arg]
+		if tv.Value == nil || tv.Value.Kind() != constant.String {
+			return nil, []error{errors.New("names given to Qualify must be string constants")}
+		}
+		name := constant.StringVal(tv.Value)
+		if name != "" && !token.IsIdentifier(name) {
+			return nil, []error{fmt.Errorf("name %q given to Qualify is not a valid identifier", name)}
+		}
+		names = append(names, name)
+	}
+	expr := astutil.Unparen(call.Args[0])
+	sig, ok := info.TypeOf(expr).(*types.Signature)
+	if !ok {
+		return nil, []error{errors.New("first argument to Qualify must be a provider function")}
+	}
+	if len(names) != sig.Params().Len() {
+		return nil, []error{fmt.Errorf("Qualify needs a name for each of the %d parameters of the provider; found %d", sig.Params().Len(), len(names))}
+	}
+	if fn, typeArgs := funcInstance(info, expr); fn != nil {
+		// Instances are not cached, as in processExpr.
+		return processFuncInstance(oc.fset, fn, sig, typeArgs, names)
+	}
+	var fn *types.Func
+	method := false
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		if s := info.Selections[sel]; s != nil && s.Kind() == types.MethodExpr {
+			fn, method = s.Obj().(*types.Func), true
+		}
+	}
+	if fn == nil {
+		fn, _ = qualifiedIdentObject(info, expr).(*types.Func)
+	}
+	if fn == nil {
+		return nil, []error{errors.New("first argument to Qualify must be a provider function")}
+	}
+	ref := qualifyRef{fn: fn, names: strings.Join(names, ",")}
+	if p := oc.qualify[ref]; p != nil {
+		return p, nil
+	}
+	var p *Provider
+	var errs []error
+	if method {
+		p, errs = processMethodProvider(oc.fset, fn, sig, names)
+	} else {
+		p, errs = processFuncInstance(oc.fset, fn, sig, nil, names)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	oc.qualify[ref] = p
+	return p, nil
+}
+
+// qualifiedProvider returns the copy of the function provider p whose output
+// type is qualified by name.
+func (oc *objectCache) qualifiedProvider(p *Provider, name string) *Provider {
+	ref := namedRef{provider: p, name: name}
+	if named := oc.named[ref]; named != nil {
+		return named
+	}
+	named := *p
+	named.Qualifier = name
+	named.Out = []types.Type{oc.qualifiedType(name, p.Out[0])}
+	oc.named[ref] = &named
+	return &named
+}
+
+// processOptions creates a provider set from a call to autowire.Options. Each
+// option provider is qualified by optionQualifier, so that they can all be in
+// the set even though they provide the same type, and the set provides the
+// slice of the option type by listing the qualified types in order.
+func (oc *objectCache) processOptions(info *types.Info, pkgPath string, pkg *types.Package, call *ast.CallExpr) (*ProviderSet, []error) {
+	// Assumes that call.Fun is autowire.Options.
+
+	if len(call.Args) < 1 {
+		return nil, []error{errors.New("call to Options takes an option type and the option providers")}
+	}
+	argType := info.TypeOf(call.Args[0])
+	ptr, ok := argType.(*types.Pointer)
+	if !ok {
+		return nil, []error{fmt.Errorf("first argument to Options must be a pointer to the option type; found %s", typeString(argType, nil))}
+	}
+	optType := ptr.Elem()
+	pset := &ProviderSet{
+		Pos:     call.Pos(),
+		PkgPath: pkgPath,
+	}
+	list := &Provider{
+		Pkg:       pkg,
+		Name:      "Options",
+		Pos:       call.Pos(),
+		IsOptions: true,
+		Out:       []types.Type{types.NewSlice(optType)},
+	}
+	ec := new(errorCollector)
+	names := make(map[string]bool)
+	for _, arg := range call.Args[1:] {
+		item, errs := oc.processExpr(info, pkgPath, arg, "")
+		if len(errs) > 0 {
+			ec.add(errs...)
+			continue
+		}
+		p, ok := item.(*Provider)
+		if !ok || p.IsStruct || p.IsCollect || p.IsOptions || p.Qualifier != "" {
+			ec.add(notePosition(oc.fset.Position(arg.Pos()), errors.New("arguments to Options after the option type must be provider functions")))
+			continue
+		}
+		if !types.Identical(p.Out[0], optType) {
+			ec.add(notePosition(oc.fset.Position(arg.Pos()),
+				fmt.Errorf("option provider %s provides %s, not the option type %s", p.Name, typeString(p.Out[0], nil), typeString(optType, nil))))
+			continue
+		}
+		if names[p.Name] {
+			ec.add(notePosition(oc.fset.Position(arg.Pos()), fmt.Errorf("Options lists more than one option provider named %s", p.Name)))
+			continue
+		}
+		names[p.Name] = true
+		opt := oc.qualifiedProvider(p, optionQualifier(optType, p.Name))
+		pset.Providers = append(pset.Providers, opt)
+		pset.order = append(pset.order, opt.Out...)
+		list.Args = append(list.Args, ProviderInput{Type: opt.Out[0]})
+	}
+	if len(ec.errors) > 0 {
+		return nil, ec.errors
+	}
+	pset.Providers = append(pset.Providers, list)
+	pset.order = append(pset.order, list.Out...)
+	var errs []error
+	pset.providerMap, pset.srcMap, errs = buildProviderMap(oc.fset, oc.hasher, pset)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return pset, nil
+}
+
+// optionQualifier returns the qualifier of the option provider named name in a
+// call to autowire.Options for optType, such as
+// "Options(foo.Option).WithLogger". It is not an identifier, so it can't be
+// given by Named or Qualify or match a field tag, and the option values are
+// only passed to the options parameter.
+func optionQualifier(optType types.Type, name string) string {
+	return fmt.Sprintf("Options(%s).%s", types.TypeString(optType, (*types.Package).Name), name)
+}
+
+// processDefault creates a default provider from a call to autowire.Default.
+func (oc *objectCache) processDefault(info *types.Info, pkgPath string, call *ast.CallExpr) (*DefaultProvider, []error) {
+	// Assumes that call.Fun is autowire.Default.
+
+	if len(call.Args) != 2 {
+		return nil, []error{errors.New("call to Default takes exactly two arguments")}
+	}
+	ptr, ok := info.TypeOf(call.Args[0]).(*types.Pointer)
+	if !ok {
+		return nil, []error{errors.New("first argument to Default must be a pointer to an interface type")}
+	}
+	iface := ptr.Elem()
+	methodSet, ok := iface.Underlying().(*types.Interface)
+	if !ok {
+		return nil, []error{errors.New("first argument to Default must be a pointer to an interface type")}
+	}
+	item, errs := oc.processExpr(info, pkgPath, call.Args[1], "")
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	p, ok := item.(*Provider)
+	if !ok || p.IsStruct || p.IsCollect || p.IsOptions {
+		return nil, []error{errors.New("second argument to Default must be a provider function")}
+	}
+	if !types.Implements(p.Out[0], methodSet) {
+		return nil, []error{fmt.Errorf("default provider %s provides %s, which does not implement %s", p.Name, typeString(p.Out[0], nil), typeString(iface, nil))}
+	}
+	return &DefaultProvider{
+		Iface:    iface,
+		Provider: p,
+		Pos:      call.Pos(),
+	}, nil
+}
+
+// processLateBind creates a late binding from a call to autowire.LateBind.
+func processLateBind(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*LateBinding, error) {
+	// Assumes that call.Fun is autowire.LateBind.
+
+	if len(call.Args) != 1 {
+		return nil, errors.New("call to LateBind takes exactly one argument")
+	}
+	lb := &LateBinding{Pos: call.Pos()}
+	arg := astutil.Unparen(call.Args[0])
+	if sel, ok := arg.(*ast.SelectorExpr); ok && info.Selections[sel] != nil && info.Selections[sel].Kind() == types.MethodExpr {
+		fn := info.Selections[sel].Obj().(*types.Func)
+		lb.Pkg, lb.Name, lb.Method = fn.Pkg(), fn.Name(), true
+	} else if fn, ok := qualifiedIdentObject(info, arg).(*types.Func); ok {
+		lb.Pkg, lb.Name = fn.Pkg(), fn.Name()
+	} else {
+		return nil, errors.New("argument to LateBind must be a function or a method expression")
+	}
+	sig := info.TypeOf(arg).(*types.Signature)
+	if sig.TypeParams().Len() > 0 {
+		return nil, fmt.Errorf("setter %s passed to LateBind can't be generic", lb.Name)
+	}
+	if sig.Params().Len() != 2 || sig.Results().Len() != 0 || sig.Variadic() {
+		return nil, fmt.Errorf("setter %s passed to LateBind must take the value to set and an interface value, and return nothing", lb.Name)
+	}
+	lb.Target = sig.Params().At(0).Type()
+	lb.Iface = sig.Params().At(1).Type()
+	if !types.IsInterface(lb.Iface) {
+		return nil, fmt.Errorf("second parameter of setter %s passed to LateBind must be an interface type; found %s", lb.Name, typeString(lb.Iface, nil))
+	}
+	return lb, nil
+}
+
+// description returns a string describing the hook, such as
+// `autowire.After hook "Validate" (example.com/foo/foo.go:10:2)`.
+func (h *Hook) description(fset *token.FileSet) string {
+	marker := "Before"
+	if h.After {
+		marker = "After"
+	}
+	return fmt.Sprintf("autowire.%s hook %q (%s)", marker, h.Name, fset.Position(h.Pos))
+}
+
+// description returns a string describing the late binding, such as
+// `autowire.LateBind setter "SetCoordinator" (example.com/foo/foo.go:10:2)`.
+func (lb *LateBinding) description(fset *token.FileSet) string {
+	return fmt.Sprintf("autowire.LateBind setter %q (%s)", lb.Name, fset.Position(lb.Pos))
+}
+
+// processHook creates a hook from a call to autowire.Before or autowire.After,
+// which is named by marker.
+func processHook(info *types.Info, call *ast.CallExpr, marker string) (*Hook, error) {
+	if len(call.Args) != 1 {
+		return nil, fmt.Errorf("call to %s takes exactly one argument", marker)
+	}
+	h := &Hook{After: marker == "After", Pos: call.Pos()}
+	arg := astutil.Unparen(call.Args[0])
+	if sel, ok := arg.(*ast.SelectorExpr); ok && info.Selections[sel] != nil && info.Selections[sel].Kind() == types.MethodExpr {
+		fn := info.Selections[sel].Obj().(*types.Func)
+		h.Pkg, h.Name, h.Method = fn.Pkg(), fn.Name(), true
+	} else if fn, ok := qualifiedIdentObject(info, arg).(*types.Func); ok {
+		h.Pkg, h.Name = fn.Pkg(), fn.Name()
+	} else {
+		return nil, fmt.Errorf("argument to %s must be a function or a method expression", marker)
+	}
+	sig := info.TypeOf(arg).(*types.Signature)
+	if sig.TypeParams().Len() > 0 {
+		return nil, fmt.Errorf("hook %s passed to %s can't be generic", h.Name, marker)
+	}
+	if sig.Variadic() {
+		return nil, fmt.Errorf("hook %s passed to %s can't be variadic", h.Name, marker)
+	}
+	switch results := sig.Results(); {
+	case results.Len() == 0:
+	case results.Len() == 1 && types.Identical(results.At(0).Type(), errorType):
+		h.HasErr = true
+	default:
+		return nil, fmt.Errorf("hook %s passed to %s must return nothing or an error", h.Name, marker)
+	}
+	for i := 0; i < sig.Params().Len(); i++ {
+		h.Args = append(h.Args, sig.Params().At(i).Type())
+	}
+	return h, nil
+}
+
+// processOptional creates a provider from a call to autowire.Optional. It is a
+// copy of the given provider whose inputs of the given types are optional.
+func (oc *objectCache) processOptional(info *types.Info, pkgPath string, call *ast.CallExpr) (*Provider, []error) {
+	// Assumes that call.Fun is autowire.Optional.
+
+	if len(call.Args) < 2 {
+		return nil, []error{errors.New("call to Optional takes a provider and at least one input type")}
+	}
+	item, errs := oc.processExpr(info, pkgPath, call.Args[0], "")
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	p, ok := item.(*Provider)
+	if !ok || p.IsCollect {
+		return nil, []error{errors.New("first argument to Optional must be a provider function or a struct provider")}
+	}
+	optional := make([]bool, len(p.Args))
+	for _, arg := range call.Args[1:] {
+		ptr, ok := info.TypeOf(arg).(*types.Pointer)
+		if !ok {
+			return nil, []error{fmt.Errorf("input types given to Optional must be pointers to the types; found %s", typeString(info.TypeOf(arg), nil))}
+		}
+		t := ptr.Elem()
+		if !isNillable(t) {
+			return nil, []error{fmt.Errorf("optional input %s must be a pointer, interface, slice, map, channel or function type, since it is passed as nil when nothing provides it", typeString(t, nil))}
+		}
+		found := false
+		for i := range p.Args {
+			if types.Identical(p.Args[i].Type, t) {
+				optional[i] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, []error{fmt.Errorf("provider %s has no input of type %s", p.Name, typeString(t, nil))}
+		}
+	}
+	var inputs []string
+	for i := range optional {
+		if optional[i] {
+			inputs = append(inputs, strconv.Itoa(i))
+		}
+	}
+	ref := optionalRef{provider: p, inputs: strings.Join(inputs, ",")}
+	if opt := oc.optional[ref]; opt != nil {
+		return opt, nil
+	}
+	opt := *p
+	opt.Args = append([]ProviderInput(nil), p.Args...)
+	for i := range opt.Args {
+		opt.Args[i].Optional = opt.Args[i].Optional || optional[i]
+	}
+	oc.optional[ref] = &opt
+	return &opt, nil
+}
+
+// processLazy creates a provider from an autowire.Lazy call, which is a
+// copy of the given provider that provides a factory for its output instead.
+func (oc *objectCache) processLazy(info *types.Info, pkgPath string, call *ast.CallExpr) (*Provider, []error) {
+	// Assumes that call.Fun is autowire.Lazy.
+
+	if len(call.Args) != 1 {
+		return nil, []error{errors.New("call to Lazy takes exactly one argument")}
+	}
+	item, errs := oc.processExpr(info, pkgPath, call.Args[0], "")
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	p, ok := item.(*Provider)
+	if !ok || p.IsStruct || p.IsCollect || p.IsOptions || p.IsLazy {
+		return nil, []error{errors.New("argument to Lazy must be a provider function or a call to Optional")}
+	}
+	if p.Qualifier != "" {
+		return nil, []error{fmt.Errorf("provider %s given to Lazy is qualified with autowire.Named; qualify the lazy provider instead", p.Name)}
+	}
+	if lazy := oc.lazy[p]; lazy != nil {
+		return lazy, nil
+	}
+	results := []*types.Var{types.NewVar(token.NoPos, nil, "", p.Out[0])}
+	if p.HasCleanup {
+		results = append(results, types.NewVar(token.NoPos, nil, "", cleanupType))
+	}
+	if p.HasErr {
+		results = append(results, types.NewVar(token.NoPos, nil, "", errorType))
+	}
+	lazy := *p
+	lazy.IsLazy = true
+	lazy.Out = []types.Type{types.NewSignature(nil, nil, types.NewTuple(results...), false)}
+	lazy.HasCleanup = false
+	lazy.HasErr = false
+	oc.lazy[p] = &lazy
+	return &lazy, nil
+}
+
+// optionsList returns the provider of the slice of options if set was created
+// by autowire.Options, or nil otherwise.
+func optionsList(set *ProviderSet) *Provider {
+	if n := len(set.Providers); n > 0 && set.Providers[n-1].IsOptions {
+		return set.Providers[n-1]
+	}
+	return nil
+}
+
+// isNillable reports whether nil is a valid value of t.
+func isNillable(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Slice, *types.Map, *types.Chan, *types.Signature:
+		return true
+	}
+	return false
+}
+
+// qualifierTag is the tag of the only field of a qualified type's underlying
+// struct type.
+const qualifierTag = `autowire:"qualifier"`
+
+// qualifiedType returns the type that stands for t qualified by name in the
+// dependency graph. It is a named type without a package, whose name is the
+// qualifier and whose underlying type is a struct holding t. It is never
+// identical or assignable to a type in a package, so it only fills in the
+// dependencies that ask for it by name.
+func (oc *objectCache) qualifiedType(name string, t types.Type) types.Type {
+	m := oc.qualified[name]
+	if m == nil {
+		m = new(typeutil.Map)
+		m.SetHasher(oc.hasher)
+		oc.qualified[name] = m
+	}
+	if q := m.At(t); q != nil {
+		return q.(types.Type)
+	}
+	st := types.NewStruct([]*types.Var{types.NewField(token.NoPos, nil, "T", t, false)}, []string{qualifierTag})
+	q := types.NewNamed(types.NewTypeName(token.NoPos, nil, name, nil), st, nil)
+	m.Set(t, q)
+	return q
+}
+
+// qualifier reports whether t is a type created by qualifiedType. If so, it
+// returns the qualifier and the type it qualifies.
+func qualifier(t types.Type) (string, types.Type, bool) {
+	n, ok := t.(*types.Named)
+	if !ok || n.Obj().Pkg() != nil {
+		return "", nil, false
+	}
+	st, ok := n.Underlying().(*types.Struct)
+	if !ok || st.NumFields() != 1 || st.Tag(0) != qualifierTag {
+		return "", nil, false
+	}
+	return n.Obj().Name(), st.Field(0).Type(), true
+}
+
+// typeString is types.TypeString, except that a type created by qualifiedType
+// is written as the type it qualifies followed by its qualifier, such as
+// `*example.com/foo.DB (named "primary")`.
+func typeString(t types.Type, qf types.Qualifier) string {
+	q, qt, ok := qualifier(t)
+	if !ok {
+		return types.TypeString(t, qf)
+	}
+	if !token.IsIdentifier(q) {
+		// See optionQualifier.
+		return fmt.Sprintf("%s (autowire.Options provider %s)", types.TypeString(qt, qf), q[strings.LastIndex(q, ".")+1:])
+	}
+	return fmt.Sprintf("%s (named %q)", types.TypeString(qt, qf), q)
+}
+
 func injectorFuncSignature(sig *types.Signature) (*types.Tuple, outputSignature, error) {
 	out, err := funcOutput(sig)
 	if err != nil {
@@ -734,14 +1594,14 @@ func funcOutput(sig *types.Signature) (outputSignature, error) {
 		case types.Identical(t, cleanupType):
 			return outputSignature{out: out, cleanup: true}, nil
 		default:
-			return outputSignature{}, fmt.Errorf("second return type is %s; must be error or func()", types.TypeString(t, nil))
+			return outputSignature{}, fmt.Errorf("second return type is %s; must be error or func()", typeString(t, nil))
 		}
 	case 3:
 		if t := results.At(1).Type(); !types.Identical(t, cleanupType) {
-			return outputSignature{}, fmt.Errorf("second return type is %s; must be func()", types.TypeString(t, nil))
+			return outputSignature{}, fmt.Errorf("second return type is %s; must be func()", typeString(t, nil))
 		}
 		if t := results.At(2).Type(); !types.Identical(t, errorType) {
-			return outputSignature{}, fmt.Errorf("third return type is %s; must be error", types.TypeString(t, nil))
+			return outputSignature{}, fmt.Errorf("third return type is %s; must be error", typeString(t, nil))
 		}
 		return outputSignature{
 			out:     results.At(0).Type(),
@@ -788,7 +1648,7 @@ func processStructLiteralProvider(fset *token.FileSet, typeName *types.TypeName)
 		}
 		for j := 0; j < i; j++ {
 			if types.Identical(provider.Args[i].Type, provider.Args[j].Type) {
-				return nil, []error{notePosition(fset.Position(pos), fmt.Errorf("provider struct has multiple fields of type %s", types.TypeString(provider.Args[j].Type, nil)))}
+				return nil, []error{notePosition(fset.Position(pos), fmt.Errorf("provider struct has multiple fields of type %s", typeString(provider.Args[j].Type, nil)))}
 			}
 		}
 	}
@@ -809,13 +1669,13 @@ func processStructProvider(fset *token.FileSet, info *types.Info, call *ast.Call
 	structPtr, ok := structType.(*types.Pointer)
 	if !ok {
 		return nil, notePosition(fset.Position(call.Pos()),
-			fmt.Errorf(firstArgReqFormat, types.TypeString(structType, nil)))
+			fmt.Errorf(firstArgReqFormat, typeString(structType, nil)))
 	}
 
 	st, ok := structPtr.Elem().Underlying().(*types.Struct)
 	if !ok {
 		return nil, notePosition(fset.Position(call.Pos()),
-			fmt.Errorf(firstArgReqFormat, types.TypeString(structPtr, nil)))
+			fmt.Errorf(firstArgReqFormat, typeString(structPtr, nil)))
 	}
 
 	stExpr := call.Args[0].(*ast.CallExpr)
@@ -828,56 +1688,98 @@ func processStructProvider(fset *token.FileSet, info *types.Info, call *ast.Call
 		Out:      []types.Type{structPtr.Elem(), structPtr},
 	}
 	if allFields(call) {
+		// The arguments after "*" override the tags of the fields they name.
+		overrides := make(map[string]fieldTag)
+		for _, arg := range call.Args[2:] {
+			v, tag, err := checkFieldOverride(arg, st, true)
+			if err != nil {
+				return nil, notePosition(fset.Position(call.Pos()), err)
+			}
+			if _, dup := overrides[v.Name()]; dup {
+				return nil, notePosition(fset.Position(call.Pos()), fmt.Errorf("field %s is listed more than once in call to Struct", v.Name()))
+			}
+			overrides[v.Name()] = tag
+		}
 		for i := 0; i < st.NumFields(); i++ {
-			if isPrevented(st.Tag(i)) {
+			f := st.Field(i)
+			tag, ok := overrides[f.Name()]
+			if !ok {
+				tag = parseFieldTag(st.Tag(i))
+			}
+			if tag.prevented {
 				continue
 			}
-			f := st.Field(i)
 			provider.Args = append(provider.Args, ProviderInput{
 				Type:      f.Type(),
 				FieldName: f.Name(),
+				TypeName:  tag.typeName,
 			})
 		}
 	} else {
 		provider.Args = make([]ProviderInput, len(call.Args)-1)
 		for i := 1; i < len(call.Args); i++ {
-			v, err := checkField(call.Args[i], st)
+			v, tag, err := checkFieldOverride(call.Args[i], st, false)
 			if err != nil {
 				return nil, notePosition(fset.Position(call.Pos()), err)
 			}
 			provider.Args[i-1] = ProviderInput{
 				Type:      v.Type(),
 				FieldName: v.Name(),
+				TypeName:  tag.typeName,
 			}
 		}
 	}
 	for i := 0; i < len(provider.Args); i++ {
 		for j := 0; j < i; j++ {
-			if types.Identical(provider.Args[i].Type, provider.Args[j].Type) {
+			if types.Identical(provider.Args[i].Type, provider.Args[j].Type) && provider.Args[i].TypeName == provider.Args[j].TypeName {
 				f := st.Field(j)
-				return nil, notePosition(fset.Position(f.Pos()), fmt.Errorf("provider struct has multiple fields of type %s", types.TypeString(provider.Args[j].Type, nil)))
+				return nil, notePosition(fset.Position(f.Pos()), fmt.Errorf("provider struct has multiple fields of type %s", typeString(provider.Args[j].Type, nil)))
 			}
 		}
 	}
 	return provider, nil
 }
 
+// allFields reports whether the field arguments of a call to autowire.Struct
+// start with "*".
 func allFields(call *ast.CallExpr) bool {
-	if len(call.Args) != 2 {
+	if len(call.Args) < 2 {
 		return false
 	}
-	b, ok := call.Args[1].(*ast.BasicLit)
+	return isWildcard(call.Args[1])
+}
+
+func isWildcard(f ast.Expr) bool {
+	b, ok := f.(*ast.BasicLit)
 	if !ok {
 		return false
 	}
 	return strings.EqualFold(strconv.Quote("*"), b.Value)
 }
 
+// fieldTag is the parsed autowire tag of a struct field.
+type fieldTag struct {
+	// prevented is true if the field is tagged `autowire:"-"`, which prevents
+	// it from being injected.
+	prevented bool
+
+	// typeName is the type name given by an `autowire:"Name"` tag. The name
+	// may be qualified by a package name, as in `autowire:"pkg.Name"`.
+	typeName string
+}
+
+// parseFieldTag parses the autowire key of a struct field's tag.
+func parseFieldTag(tag string) fieldTag {
+	v := reflect.StructTag(tag).Get("autowire")
+	if v == "-" {
+		return fieldTag{prevented: true}
+	}
+	return fieldTag{typeName: v}
+}
+
 // isPrevented checks whether field i is prevented by tag "-".
-// Since this is the only tag used by autowire, we can do string comparison
-// without using reflect.
 func isPrevented(tag string) bool {
-	return reflect.StructTag(tag).Get("autowire") == "-"
+	return parseFieldTag(tag).prevented
 }
 
 // processBind creates an interface binding from a autowire.Bind call.
@@ -893,13 +1795,13 @@ func processBind(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*If
 	ifacePtr, ok := ifaceArgType.(*types.Pointer)
 	if !ok {
 		return nil, notePosition(fset.Position(call.Pos()),
-			fmt.Errorf("first argument to Bind must be a pointer to an interface type; found %s", types.TypeString(ifaceArgType, nil)))
+			fmt.Errorf("first argument to Bind must be a pointer to an interface type; found %s", typeString(ifaceArgType, nil)))
 	}
 	iface := ifacePtr.Elem()
 	methodSet, ok := iface.Underlying().(*types.Interface)
 	if !ok {
 		return nil, notePosition(fset.Position(call.Pos()),
-			fmt.Errorf("first argument to Bind must be a pointer to an interface type; found %s", types.TypeString(ifaceArgType, nil)))
+			fmt.Errorf("first argument to Bind must be a pointer to an interface type; found %s", typeString(ifaceArgType, nil)))
 	}
 
 	provided := info.TypeOf(call.Args[1])
@@ -907,7 +1809,7 @@ func processBind(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*If
 		providedPtr, ok := provided.(*types.Pointer)
 		if !ok {
 			return nil, notePosition(fset.Position(call.Args[0].Pos()),
-				fmt.Errorf("second argument to Bind must be a pointer or a pointer to a pointer; found %s", types.TypeString(provided, nil)))
+				fmt.Errorf("second argument to Bind must be a pointer or a pointer to a pointer; found %s", typeString(provided, nil)))
 		}
 		provided = providedPtr.Elem()
 	}
@@ -917,7 +1819,7 @@ func processBind(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*If
 	}
 	if !types.Implements(provided, methodSet) {
 		return nil, notePosition(fset.Position(call.Pos()),
-			fmt.Errorf("%s does not implement %s", types.TypeString(provided, nil), types.TypeString(iface, nil)))
+			notImplementedError(provided, iface, methodSet))
 	}
 	return &IfaceBinding{
 		Pos:      call.Pos(),
@@ -926,6 +1828,90 @@ func processBind(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*If
 	}, nil
 }
 
+// processBindAll creates the interface bindings of a autowire.BindAll call:
+// one for each interface it lists, all bound to the concrete type that is its
+// first argument.
+func processBindAll(fset *token.FileSet, info *types.Info, call *ast.CallExpr) ([]*IfaceBinding, error) {
+	// Assumes that call.Fun is autowire.BindAll.
+
+	if len(call.Args) < 2 {
+		return nil, notePosition(fset.Position(call.Pos()),
+			errors.New("call to BindAll takes a concrete type and at least one interface"))
+	}
+	provided := info.TypeOf(call.Args[0])
+	if bindShouldUsePointer(info, call) {
+		providedPtr, ok := provided.(*types.Pointer)
+		if !ok {
+			return nil, notePosition(fset.Position(call.Args[0].Pos()),
+				fmt.Errorf("first argument to BindAll must be a pointer or a pointer to a pointer; found %s", typeString(provided, nil)))
+		}
+		provided = providedPtr.Elem()
+	}
+	bindings := make([]*IfaceBinding, 0, len(call.Args)-1)
+	for i, arg := range call.Args[1:] {
+		ifaceArgType := info.TypeOf(arg)
+		ifacePtr, ok := ifaceArgType.(*types.Pointer)
+		if !ok {
+			return nil, notePosition(fset.Position(arg.Pos()),
+				fmt.Errorf("argument %d to BindAll must be a pointer to an interface type; found %s", i+2, typeString(ifaceArgType, nil)))
+		}
+		iface := ifacePtr.Elem()
+		methodSet, ok := iface.Underlying().(*types.Interface)
+		if !ok {
+			return nil, notePosition(fset.Position(arg.Pos()),
+				fmt.Errorf("argument %d to BindAll must be a pointer to an interface type; found %s", i+2, typeString(ifaceArgType, nil)))
+		}
+		for _, prev := range bindings {
+			if types.Identical(prev.Iface, iface) {
+				return nil, notePosition(fset.Position(arg.Pos()),
+					fmt.Errorf("%s is listed more than once in call to BindAll", typeString(iface, nil)))
+			}
+		}
+		if types.Identical(iface, provided) {
+			return nil, notePosition(fset.Position(arg.Pos()),
+				errors.New("cannot bind interface to itself"))
+		}
+		if !types.Implements(provided, methodSet) {
+			return nil, notePosition(fset.Position(arg.Pos()),
+				notImplementedError(provided, iface, methodSet))
+		}
+		bindings = append(bindings, &IfaceBinding{
+			Pos:      call.Pos(),
+			Iface:    iface,
+			Provided: provided,
+		})
+	}
+	return bindings, nil
+}
+
+// processCollect creates a provider from a call to autowire.Collect. pkg is the
+// autowire package.
+func processCollect(fset *token.FileSet, info *types.Info, pkg *types.Package, call *ast.CallExpr) (*Provider, error) {
+	// Assumes that call.Fun is autowire.Collect.
+
+	if len(call.Args) != 1 {
+		return nil, notePosition(fset.Position(call.Pos()),
+			errors.New("call to Collect takes exactly one argument"))
+	}
+	argType := info.TypeOf(call.Args[0])
+	ptr, ok := argType.(*types.Pointer)
+	if !ok {
+		return nil, notePosition(fset.Position(call.Pos()),
+			fmt.Errorf("argument to Collect must be a pointer to a slice type; found %s", typeString(argType, nil)))
+	}
+	if _, ok := ptr.Elem().(*types.Slice); !ok {
+		return nil, notePosition(fset.Position(call.Pos()),
+			fmt.Errorf("argument to Collect must be a pointer to a slice type; found %s", typeString(argType, nil)))
+	}
+	return &Provider{
+		Pkg:       pkg,
+		Name:      "Collect",
+		Pos:       call.Pos(),
+		IsCollect: true,
+		Out:       []types.Type{ptr.Elem()},
+	}, nil
+}
+
 // processValue creates a value from a autowire.Value call.
 func processValue(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*Value, error) {
 	// Assumes that call.Fun is autowire.Value.
@@ -961,7 +1947,7 @@ func processValue(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*V
 	// Result type can't be an interface type; use autowire.InterfaceValue for that.
 	argType := info.TypeOf(call.Args[0])
 	if _, isInterfaceType := argType.Underlying().(*types.Interface); isInterfaceType {
-		return nil, notePosition(fset.Position(call.Pos()), fmt.Errorf("argument to Value may not be an interface value (found %s); use InterfaceValue instead", types.TypeString(argType, nil)))
+		return nil, notePosition(fset.Position(call.Pos()), fmt.Errorf("argument to Value may not be an interface value (found %s); use InterfaceValue instead", typeString(argType, nil)))
 	}
 	return &Value{
 		Pos:  call.Args[0].Pos(),
@@ -971,6 +1957,23 @@ func processValue(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*V
 	}, nil
 }
 
+// notImplementedError returns an error explaining why provided does not
+// implement iface, whose underlying type is methodSet.
+func notImplementedError(provided, iface types.Type, methodSet *types.Interface) error {
+	err := fmt.Errorf("%s does not implement %s", typeString(provided, nil), typeString(iface, nil))
+	method, wrongType := types.MissingMethod(provided, methodSet, true)
+	switch {
+	case method == nil:
+		return err
+	case wrongType:
+		return fmt.Errorf("%v (wrong type for method %s)", err, method.Name())
+	case !types.IsInterface(provided) && types.Implements(types.NewPointer(provided), methodSet):
+		return fmt.Errorf("%v (method %s has pointer receiver)", err, method.Name())
+	default:
+		return fmt.Errorf("%v (missing method %s)", err, method.Name())
+	}
+}
+
 // processInterfaceValue creates a value from a autowire.InterfaceValue call.
 func processInterfaceValue(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*Value, error) {
 	// Assumes that call.Fun is autowire.InterfaceValue.
@@ -981,16 +1984,16 @@ func processInterfaceValue(fset *token.FileSet, info *types.Info, call *ast.Call
 	ifaceArgType := info.TypeOf(call.Args[0])
 	ifacePtr, ok := ifaceArgType.(*types.Pointer)
 	if !ok {
-		return nil, notePosition(fset.Position(call.Pos()), fmt.Errorf("first argument to InterfaceValue must be a pointer to an interface type; found %s", types.TypeString(ifaceArgType, nil)))
+		return nil, notePosition(fset.Position(call.Pos()), fmt.Errorf("first argument to InterfaceValue must be a pointer to an interface type; found %s", typeString(ifaceArgType, nil)))
 	}
 	iface := ifacePtr.Elem()
 	methodSet, ok := iface.Underlying().(*types.Interface)
 	if !ok {
-		return nil, notePosition(fset.Position(call.Pos()), fmt.Errorf("first argument to InterfaceValue must be a pointer to an interface type; found %s", types.TypeString(ifaceArgType, nil)))
+		return nil, notePosition(fset.Position(call.Pos()), fmt.Errorf("first argument to InterfaceValue must be a pointer to an interface type; found %s", typeString(ifaceArgType, nil)))
 	}
 	provided := info.TypeOf(call.Args[1])
 	if !types.Implements(provided, methodSet) {
-		return nil, notePosition(fset.Position(call.Pos()), fmt.Errorf("%s does not implement %s", types.TypeString(provided, nil), types.TypeString(iface, nil)))
+		return nil, notePosition(fset.Position(call.Pos()), notImplementedError(provided, iface, methodSet))
 	}
 	return &Value{
 		Pos:  call.Args[1].Pos(),
@@ -1001,8 +2004,9 @@ func processInterfaceValue(fset *token.FileSet, info *types.Info, call *ast.Call
 }
 
 // processFieldsOf creates a slice of fields from a autowire.FieldsOf call.
-func processFieldsOf(fset *token.FileSet, info *types.Info, call *ast.CallExpr) ([]*Field, error) {
+func (oc *objectCache) processFieldsOf(info *types.Info, pkgPath string, call *ast.CallExpr) ([]*Field, error) {
 	// Assumes that call.Fun is autowire.FieldsOf.
+	fset := oc.fset
 
 	if len(call.Args) < 2 {
 		return nil, notePosition(fset.Position(call.Pos()),
@@ -1013,7 +2017,7 @@ func processFieldsOf(fset *token.FileSet, info *types.Info, call *ast.CallExpr)
 	structPtr, ok := structType.(*types.Pointer)
 	if !ok {
 		return nil, notePosition(fset.Position(call.Pos()),
-			fmt.Errorf(firstArgReqFormat, types.TypeString(structType, nil)))
+			fmt.Errorf(firstArgReqFormat, typeString(structType, nil)))
 	}
 
 	var struc *types.Struct
@@ -1023,59 +2027,289 @@ func processFieldsOf(fset *token.FileSet, info *types.Info, call *ast.CallExpr)
 		struc, ok = t.Elem().Underlying().(*types.Struct)
 		if !ok {
 			return nil, notePosition(fset.Position(call.Pos()),
-				fmt.Errorf(firstArgReqFormat, types.TypeString(struc, nil)))
+				fmt.Errorf(firstArgReqFormat, typeString(struc, nil)))
 		}
 		isPtrToStruct = true
 	case *types.Struct:
 		struc = t
 	default:
 		return nil, notePosition(fset.Position(call.Pos()),
-			fmt.Errorf(firstArgReqFormat, types.TypeString(t, nil)))
+			fmt.Errorf(firstArgReqFormat, typeString(t, nil)))
 	}
-	if struc.NumFields() < len(call.Args)-1 {
+	wildcard := isWildcard(call.Args[1])
+	if !wildcard && struc.NumFields() < len(call.Args)-1 && !hasEmbeddedField(struc) {
 		return nil, notePosition(fset.Position(call.Pos()),
 			fmt.Errorf("fields number exceeds the number available in the struct which has %d fields", struc.NumFields()))
 	}
 
-	fields := make([]*Field, 0, len(call.Args)-1)
-	for i := 1; i < len(call.Args); i++ {
-		v, err := checkField(call.Args[i], struc)
-		if err != nil {
-			return nil, notePosition(fset.Position(call.Pos()), err)
+	var vars []*types.Var
+	qualifiers := make(map[*types.Var]string)
+	if wildcard {
+		// The arguments after "*" qualify or leave out the fields they name.
+		overridden := make(map[*types.Var]bool)
+		excluded := make(map[*types.Var]bool)
+		for _, arg := range call.Args[2:] {
+			name, q, err := fieldsOfArg(arg, true)
+			if err != nil {
+				return nil, notePosition(fset.Position(call.Pos()), err)
+			}
+			v, _, err := checkField(stringLit(arg, strings.TrimPrefix(name, "-")), struc)
+			if err != nil {
+				return nil, notePosition(fset.Position(call.Pos()), err)
+			}
+			if overridden[v] {
+				return nil, notePosition(fset.Position(call.Pos()), fmt.Errorf("field %s is listed more than once in call to FieldsOf", v.Name()))
+			}
+			overridden[v] = true
+			if strings.HasPrefix(name, "-") {
+				excluded[v] = true
+			} else {
+				qualifiers[v] = q
+			}
 		}
+		for i := 0; i < struc.NumFields(); i++ {
+			v := struc.Field(i)
+			if excluded[v] || isPrevented(struc.Tag(i)) || v.Pkg().Path() != pkgPath && !v.Exported() {
+				continue
+			}
+			vars = append(vars, v)
+		}
+	} else {
+		for _, arg := range call.Args[1:] {
+			name, q, err := fieldsOfArg(arg, false)
+			if err != nil {
+				return nil, notePosition(fset.Position(call.Pos()), err)
+			}
+			f := stringLit(arg, name)
+			v, ok, err := promotedField(f, structPtr.Elem(), struc, oc.maxFieldDepth)
+			if !ok {
+				v, _, err = checkField(f, struc)
+			}
+			if err != nil {
+				return nil, notePosition(fset.Position(call.Pos()), err)
+			}
+			vars = append(vars, v)
+			qualifiers[v] = q
+		}
+	}
+
+	fields := make([]*Field, 0, len(vars))
+	for _, v := range vars {
 		out := []types.Type{v.Type()}
 		if isPtrToStruct {
 			// If the field is from a pointer to a struct, then
 			// autowire.Fields also provides a pointer to the field.
 			out = append(out, types.NewPointer(v.Type()))
 		}
-		fields = append(fields, &Field{
+		if q := qualifiers[v]; q != "" {
+			for i := range out {
+				out[i] = oc.qualifiedType(q, out[i])
+			}
+		}
+		f := &Field{
 			Parent: structPtr.Elem(),
 			Name:   v.Name(),
 			Pkg:    v.Pkg(),
 			Pos:    v.Pos(),
 			Out:    out,
-		})
+		}
+		if wildcard {
+			f.Wildcard = call.Pos()
+			for _, prev := range fields {
+				if types.Identical(prev.Out[0], out[0]) {
+					return nil, notePosition(fset.Position(call.Pos()),
+						fmt.Errorf(`fields %s and %s of %s both have type %s; qualify one of them with "%s=name" to provide it as if by autowire.Named, or leave it out with "-%s"`,
+							prev.Name, v.Name(), typeString(structPtr.Elem(), nil), typeString(out[0], nil), v.Name(), v.Name()))
+				}
+			}
+		}
+		fields = append(fields, f)
 	}
 	return fields, nil
 }
 
+// fieldsOfArg parses a field argument to autowire.FieldsOf, which is either
+// a field name or "Name=qualifier", and, if wildcard is true because it
+// follows "*", may also be "-Name". It returns the field name, with any
+// leading "-", and the qualifier.
+func fieldsOfArg(f ast.Expr, wildcard bool) (name, qualifier string, _ error) {
+	b, ok := f.(*ast.BasicLit)
+	if !ok || b.Kind != token.STRING {
+		return "", "", fmt.Errorf("%v must be a string with the field name", f)
+	}
+	if isWildcard(f) {
+		return "", "", errors.New(`"*" must be the first field name passed to FieldsOf`)
+	}
+	arg, err := strconv.Unquote(b.Value)
+	if err != nil {
+		return "", "", fmt.Errorf("%v must be a string with the field name", f)
+	}
+	if i := strings.Index(arg, "="); i >= 0 {
+		if arg[i+1:] == "" {
+			return "", "", fmt.Errorf("%s must give the qualifier to provide field %s with after the =", b.Value, arg[:i])
+		}
+		return arg[:i], arg[i+1:], nil
+	}
+	if strings.HasPrefix(arg, "-") && !wildcard {
+		return "", "", fmt.Errorf(`%s leaves out field %s, so it must follow "*"`, b.Value, arg[1:])
+	}
+	if !strings.HasPrefix(arg, "-") && wildcard {
+		return "", "", fmt.Errorf(`field %s is already provided by "*"; use "%s=name" to qualify it`, arg, arg)
+	}
+	return arg, "", nil
+}
+
+// stringLit returns a string literal holding s at the position of f.
+func stringLit(f ast.Expr, s string) *ast.BasicLit {
+	return &ast.BasicLit{ValuePos: f.Pos(), Kind: token.STRING, Value: strconv.Quote(s)}
+}
+
 // checkField reports whether f is a field of st. f should be a string with the
-// field name.
-func checkField(f ast.Expr, st *types.Struct) (*types.Var, error) {
+// field name. It returns the field along with its parsed tag.
+func checkField(f ast.Expr, st *types.Struct) (*types.Var, fieldTag, error) {
 	b, ok := f.(*ast.BasicLit)
 	if !ok {
-		return nil, fmt.Errorf("%v must be a string with the field name", f)
+		return nil, fieldTag{}, fmt.Errorf("%v must be a string with the field name", f)
 	}
 	for i := 0; i < st.NumFields(); i++ {
 		if strings.EqualFold(strconv.Quote(st.Field(i).Name()), b.Value) {
-			if isPrevented(st.Tag(i)) {
-				return nil, fmt.Errorf("%s is prevented from injecting by autowire", b.Value)
+			tag := parseFieldTag(st.Tag(i))
+			if tag.prevented {
+				return nil, fieldTag{}, fmt.Errorf("%s is prevented from injecting by autowire", b.Value)
 			}
-			return st.Field(i), nil
+			return st.Field(i), tag, nil
 		}
 	}
-	return nil, fmt.Errorf("%s is not a field of %s", b.Value, st.String())
+	return nil, fieldTag{}, fmt.Errorf("%s is not a field of %s", b.Value, st.String())
+}
+
+// checkFieldOverride is like checkField, but also accepts "Name=Type", which
+// fills in the field as if it were tagged `autowire:"Type"`, and, if wildcard
+// is true because the argument follows "*", "-Name", which leaves the field
+// out as if it were tagged `autowire:"-"`.
+func checkFieldOverride(f ast.Expr, st *types.Struct, wildcard bool) (*types.Var, fieldTag, error) {
+	b, ok := f.(*ast.BasicLit)
+	if !ok || b.Kind != token.STRING {
+		return nil, fieldTag{}, fmt.Errorf("%v must be a string with the field name", f)
+	}
+	if isWildcard(f) {
+		return nil, fieldTag{}, errors.New(`"*" must be the first field name passed to Struct`)
+	}
+	arg, err := strconv.Unquote(b.Value)
+	if err != nil {
+		return nil, fieldTag{}, fmt.Errorf("%v must be a string with the field name", f)
+	}
+	name, typeName := arg, ""
+	override := false
+	if i := strings.Index(arg, "="); i >= 0 {
+		name, typeName, override = arg[:i], arg[i+1:], true
+	}
+	excluded := false
+	if !override {
+		excluded = strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+	}
+	v, tag, err := checkField(stringLit(f, name), st)
+	switch {
+	case err != nil:
+		return nil, fieldTag{}, err
+	case override && typeName == "":
+		return nil, fieldTag{}, fmt.Errorf("%s must name the type to fill in field %s with after the =", b.Value, v.Name())
+	case override:
+		tag.typeName = typeName
+	case excluded && !wildcard:
+		return nil, fieldTag{}, fmt.Errorf(`%s leaves out field %s, so it must follow "*"`, b.Value, v.Name())
+	case excluded:
+		tag = fieldTag{prevented: true}
+	case wildcard:
+		return nil, fieldTag{}, fmt.Errorf(`field %s is already filled in by "*"; use "%s=Type" to choose what fills it in`, v.Name(), v.Name())
+	}
+	return v, tag, nil
+}
+
+// hasEmbeddedField reports whether st has an embedded field.
+func hasEmbeddedField(st *types.Struct) bool {
+	for i := 0; i < st.NumFields(); i++ {
+		if st.Field(i).Embedded() {
+			return true
+		}
+	}
+	return false
+}
+
+// promotedField returns the field named by f that is promoted to st, the
+// underlying struct of parent, from one of its embedded structs. It returns
+// false if f is not a string, names a field declared in st itself, or names
+// no promoted field, in which case checkField reports the problem.
+//
+// If maxDepth is positive, a field promoted through maxDepth or more embedded
+// structs is an error that names the embedded struct beyond the limit.
+func promotedField(f ast.Expr, parent types.Type, st *types.Struct, maxDepth int) (*types.Var, bool, error) {
+	b, ok := f.(*ast.BasicLit)
+	if !ok || b.Kind != token.STRING {
+		return nil, false, nil
+	}
+	name, err := strconv.Unquote(b.Value)
+	if err != nil {
+		return nil, false, nil
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if strings.EqualFold(st.Field(i).Name(), name) {
+			return nil, false, nil
+		}
+	}
+	var pkg *types.Package
+	named, ok := parent.(*types.Named)
+	if ptr, isPtr := parent.(*types.Pointer); isPtr {
+		named, ok = ptr.Elem().(*types.Named)
+	}
+	if ok {
+		pkg = named.Obj().Pkg()
+	}
+	obj, index, _ := types.LookupFieldOrMethod(parent, true, pkg, name)
+	if obj == nil && index != nil {
+		return nil, true, fmt.Errorf("ambiguous selector %s: it is promoted to %s from more than one embedded struct (%s); use FieldsOf to provide the embedded struct and another FieldsOf to provide %s from it",
+			name, typeString(parent, nil), strings.Join(embeddedWith(st, pkg, name), ", "), name)
+	}
+	v, ok := obj.(*types.Var)
+	if !ok || !v.IsField() {
+		return nil, false, nil
+	}
+	// Find the struct that declares the field to check its tag.
+	var embedded []types.Type
+	for _, i := range index[:len(index)-1] {
+		t := st.Field(i).Type()
+		embedded = append(embedded, t)
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		st = t.Underlying().(*types.Struct)
+	}
+	if maxDepth > 0 && len(index) > maxDepth {
+		beyond := typeString(embedded[maxDepth-1], nil)
+		return nil, true, fmt.Errorf("field %s of %s is promoted from embedded struct %s, which is beyond the maximum field depth of %d; provide %s and use FieldsOf to provide %s from it",
+			name, typeString(parent, nil), beyond, maxDepth, beyond, name)
+	}
+	if parseFieldTag(st.Tag(index[len(index)-1])).prevented {
+		return nil, true, fmt.Errorf("%s is prevented from injecting by autowire", b.Value)
+	}
+	return v, true, nil
+}
+
+// embeddedWith returns the names of the fields embedded in st that have a
+// field or method with the given name, directly or promoted.
+func embeddedWith(st *types.Struct, pkg *types.Package, name string) []string {
+	var names []string
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if !f.Embedded() {
+			continue
+		}
+		if obj, index, _ := types.LookupFieldOrMethod(f.Type(), true, pkg, name); obj != nil || index != nil {
+			names = append(names, f.Name())
+		}
+	}
+	return names
 }
 
 // findInjectorBuild returns the autowire.Build call if fn is an injector template.
@@ -1133,6 +2367,45 @@ func findInjectorBuild(info *types.Info, fn *ast.FuncDecl) (*ast.CallExpr, error
 	return wireBuildCall, nil
 }
 
+// injectorDestination returns the import path given to autowire.Into in the
+// arguments of an injector's autowire.Build call, along with a copy of the call
+// without it. The path is empty if the call does not use autowire.Into.
+func injectorDestination(info *types.Info, buildCall *ast.CallExpr) (string, *ast.CallExpr, error) {
+	var dest string
+	var intoCall *ast.CallExpr
+	args := make([]ast.Expr, 0, len(buildCall.Args))
+	for _, arg := range buildCall.Args {
+		call, ok := astutil.Unparen(arg).(*ast.CallExpr)
+		if !ok {
+			args = append(args, arg)
+			continue
+		}
+		obj := qualifiedIdentObject(info, call.Fun)
+		if obj == nil || obj.Pkg() == nil || !isWireImport(obj.Pkg().Path()) || obj.Name() != "Into" {
+			args = append(args, arg)
+			continue
+		}
+		if intoCall != nil {
+			return "", nil, errors.New("autowire.Into may only be passed to autowire.Build once")
+		}
+		intoCall = call
+		tv := info.Types[call.Args[0]]
+		if tv.Value == nil || tv.Value.Kind() != constant.String {
+			return "", nil, errors.New("argument to autowire.Into must be a string constant")
+		}
+		dest = constant.StringVal(tv.Value)
+		if dest == "" {
+			return "", nil, errors.New("argument to autowire.Into must be an import path")
+		}
+	}
+	if intoCall == nil {
+		return "", buildCall, nil
+	}
+	setCall := *buildCall
+	setCall.Args = args
+	return dest, &setCall, nil
+}
+
 func isWireImport(path string) bool {
 	// TODO(light): This is depending on details of the current loader.
 	const vendorPart = "vendor/"
@@ -1180,6 +2453,12 @@ func (pt ProvidedType) Type() types.Type {
 	return pt.t
 }
 
+// sameSource reports whether pt and other come from the same provider,
+// value, injector argument, or field.
+func (pt ProvidedType) sameSource(other ProvidedType) bool {
+	return pt.p == other.p && pt.v == other.v && pt.a == other.a && pt.f == other.f
+}
+
 // IsProvider reports whether pt points to a Provider.
 func (pt ProvidedType) IsProvider() bool {
 	return pt.p != nil
@@ -0,0 +1,130 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autowire
+
+import (
+	"encoding/json"
+	"go/token"
+	"go/types"
+	"io"
+)
+
+// ReportVersion is the version of the Report schema. It is incremented
+// whenever a field is removed or changes meaning, so that tools can reject
+// reports they do not understand.
+const ReportVersion = 1
+
+// A Report is the provider resolution of every injector that Generate
+// generated, as written by a ReportWriter.
+type Report struct {
+	// Version is ReportVersion.
+	Version   int              `json:"version"`
+	Injectors []ReportInjector `json:"injectors"`
+}
+
+// A ReportInjector is the resolution of one injector.
+type ReportInjector struct {
+	// Name is the name of the injector function.
+	Name string `json:"name"`
+	// Package is the import path of the package that declares the injector.
+	Package string `json:"package"`
+	// Pos is the position of the injector function.
+	Pos ReportPosition `json:"pos"`
+	// Values are the values of the injector in the order it builds them,
+	// starting with its arguments.
+	Values []ReportValue `json:"values"`
+}
+
+// A ReportValue is a value that an injector receives or builds.
+type ReportValue struct {
+	// Type is the package-qualified type of the value.
+	Type string `json:"type"`
+	// Qualifier is the name that the value's type is qualified by, as with
+	// autowire.Named, if any. Type is then the type it qualifies.
+	Qualifier string `json:"qualifier,omitempty"`
+	// Kind is the GraphNode kind of what produces the value.
+	Kind string `json:"kind"`
+	// Name identifies what produces the value, as in GraphNode.
+	Name string `json:"name"`
+	// Package is the import path of the package that declares the provider,
+	// struct or field that produces the value, if any.
+	Package string `json:"package,omitempty"`
+	// Pos is the position of what produces the value.
+	Pos ReportPosition `json:"pos"`
+	// Inputs are the indices in Values of the values that the value is built
+	// from.
+	Inputs []int `json:"inputs"`
+}
+
+// A ReportPosition is a position in a source file.
+type ReportPosition struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+func reportPosition(pos token.Position) ReportPosition {
+	return ReportPosition{File: pos.Filename, Line: pos.Line, Column: pos.Column}
+}
+
+// A ReportWriter is a GraphWriter that collects the graph of each injector
+// into a Report. Close must be called after the last graph to write the report
+// as JSON.
+type ReportWriter struct {
+	w      io.Writer
+	report Report
+}
+
+// NewReportWriter returns a ReportWriter that writes to w.
+func NewReportWriter(w io.Writer) *ReportWriter {
+	return &ReportWriter{w: w, report: Report{Version: ReportVersion, Injectors: []ReportInjector{}}}
+}
+
+// WriteGraph adds g to the report.
+func (rw *ReportWriter) WriteGraph(g *InjectorGraph) error {
+	inj := ReportInjector{
+		Name:    g.Injector,
+		Package: g.Pkg,
+		Pos:     reportPosition(g.Pos),
+		Values:  make([]ReportValue, 0, len(g.Nodes)),
+	}
+	for _, n := range g.Nodes {
+		inputs := n.Inputs
+		if inputs == nil {
+			inputs = []int{}
+		}
+		v := ReportValue{
+			Type:    types.TypeString(n.Out, nil),
+			Kind:    n.Kind,
+			Name:    n.Name,
+			Package: n.Pkg,
+			Pos:     reportPosition(n.Pos),
+			Inputs:  inputs,
+		}
+		if q, qt, ok := qualifier(n.Out); ok {
+			v.Type, v.Qualifier = types.TypeString(qt, nil), q
+		}
+		inj.Values = append(inj.Values, v)
+	}
+	rw.report.Injectors = append(rw.report.Injectors, inj)
+	return nil
+}
+
+// Close writes the report. It does not close the underlying writer.
+func (rw *ReportWriter) Close() error {
+	enc := json.NewEncoder(rw.w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(&rw.report)
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autowire
+
+import (
+	"bytes"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+func TestReportWriter(t *testing.T) {
+	pkg := types.NewPackage("example.com/foo", "foo")
+	config := types.NewPointer(types.NewNamed(types.NewTypeName(0, pkg, "Config", nil), types.NewStruct(nil, nil), nil))
+	oc := &objectCache{qualified: make(map[string]*typeutil.Map), hasher: typeutil.MakeHasher()}
+	primary := oc.qualifiedType("primary", types.Typ[types.String])
+	graph := &InjectorGraph{
+		Injector: "injectAddr",
+		Pkg:      "example.com/foo",
+		Pos:      token.Position{Filename: "foo/wire.go", Line: 10, Column: 6},
+		Nodes: []GraphNode{
+			{Kind: "provider", Name: "example.com/foo.NewConfig", Pkg: "example.com/foo", Pos: token.Position{Filename: "foo/foo.go", Line: 5, Column: 6}, Out: config},
+			{Kind: "field", Name: "Addr", Pkg: "example.com/foo", Pos: token.Position{Filename: "foo/foo.go", Line: 12, Column: 2}, Out: types.Typ[types.String], Inputs: []int{0}},
+			{Kind: "provider", Name: "example.com/foo.PrimaryAddr", Pkg: "example.com/foo", Pos: token.Position{Filename: "foo/foo.go", Line: 20, Column: 6}, Out: primary, Inputs: []int{1}},
+		},
+	}
+	const want = `{
+	"version": 1,
+	"injectors": [
+		{
+			"name": "injectAddr",
+			"package": "example.com/foo",
+			"pos": {
+				"file": "foo/wire.go",
+				"line": 10,
+				"column": 6
+			},
+			"values": [
+				{
+					"type": "*example.com/foo.Config",
+					"kind": "provider",
+					"name": "example.com/foo.NewConfig",
+					"package": "example.com/foo",
+					"pos": {
+						"file": "foo/foo.go",
+						"line": 5,
+						"column": 6
+					},
+					"inputs": []
+				},
+				{
+					"type": "string",
+					"kind": "field",
+					"name": "Addr",
+					"package": "example.com/foo",
+					"pos": {
+						"file": "foo/foo.go",
+						"line": 12,
+						"column": 2
+					},
+					"inputs": [
+						0
+					]
+				},
+				{
+					"type": "string",
+					"qualifier": "primary",
+					"kind": "provider",
+					"name": "example.com/foo.PrimaryAddr",
+					"package": "example.com/foo",
+					"pos": {
+						"file": "foo/foo.go",
+						"line": 20,
+						"column": 6
+					},
+					"inputs": [
+						1
+					]
+				}
+			]
+		}
+	]
+}
+`
+	buf := new(bytes.Buffer)
+	rw := NewReportWriter(buf)
+	if err := rw.WriteGraph(graph); err != nil {
+		t.Fatal(err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("report differs (-want +got):\n%s", diff)
+	}
+
+	buf.Reset()
+	if err := NewReportWriter(buf).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "{\n\t\"version\": 1,\n\t\"injectors\": []\n}\n"; got != want {
+		t.Errorf("report with no graphs = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	s, err := injectServer(Config{Addr: ":8080"})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println(s.Describe())
+}
+
+type Config struct {
+	Addr string
+}
+
+type Limit int
+
+type Storage interface {
+	Get(key string) string
+}
+
+type Store struct {
+	limit Limit
+}
+
+func NewStore(l Limit) (*Store, error) {
+	return &Store{limit: l}, nil
+}
+
+func (s *Store) Get(key string) string {
+	return fmt.Sprintf("%s (limit %d)", key, s.limit)
+}
+
+type Handler struct {
+	Storage Storage
+	Limit   Limit
+}
+
+type Server struct {
+	addr    string
+	handler Handler
+}
+
+func NewServer(addr string, h Handler) *Server {
+	return &Server{addr: addr, handler: h}
+}
+
+func (s *Server) Describe() string {
+	return s.addr + ": " + s.handler.Storage.Get("index")
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	cleaned     []string
+	failService bool
+)
+
+func main() {
+	failService = true
+	_, _, err := injectService()
+	fmt.Println(err)
+	fmt.Println(strings.Join(cleaned, " "))
+
+	cleaned = nil
+	failService = false
+	_, cleanup, err := injectService()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	cleanup()
+	fmt.Println(strings.Join(cleaned, " "))
+}
+
+type DBConfig struct{}
+type DB struct{}
+type CacheConfig struct{}
+type Cache struct{}
+type Service struct{}
+
+func provideDBConfig() (*DBConfig, func()) {
+	return new(DBConfig), func() { cleaned = append(cleaned, "dbconfig") }
+}
+
+func provideDB(*DBConfig) (*DB, func(), error) {
+	return new(DB), func() { cleaned = append(cleaned, "db") }, nil
+}
+
+func provideCacheConfig() (*CacheConfig, func()) {
+	return new(CacheConfig), func() { cleaned = append(cleaned, "cacheconfig") }
+}
+
+func provideCache(*CacheConfig) (*Cache, func()) {
+	return new(Cache), func() { cleaned = append(cleaned, "cache") }
+}
+
+func provideService(*DB, *Cache) (*Service, func(), error) {
+	if failService {
+		return nil, nil, errors.New("service failed")
+	}
+	return new(Service), func() { cleaned = append(cleaned, "service") }, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+type key struct{}
+
+func main() {
+	ctx := context.WithValue(context.Background(), key{}, "startup")
+	srv, err := injectServer(ctx, &Config{Name: "srv"})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(srv.DB.Phase, srv.Cache.Phase)
+}
+
+type Config struct {
+	Name string
+}
+
+type DB struct {
+	Phase interface{}
+}
+
+type Cache struct {
+	Phase interface{}
+}
+
+type Server struct {
+	DB    *DB
+	Cache *Cache
+}
+
+func provideDB(ctx context.Context, cfg *Config) (*DB, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &DB{Phase: ctx.Value(key{})}, nil
+}
+
+func provideCache(ctx context.Context) *Cache {
+	return &Cache{Phase: ctx.Value(key{})}
+}
+
+func provideServer(db *DB, cache *Cache) *Server {
+	return &Server{DB: db, Cache: cache}
+}
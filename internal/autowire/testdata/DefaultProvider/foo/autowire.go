@@ -0,0 +1,48 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+func injectDefaultOnly() *Server {
+	// Nothing else provides Logger, so the default is used.
+	autowire.Build(DefaultSet)
+	return nil
+}
+
+func injectBound() *Server {
+	// An explicit binding takes precedence over the default.
+	autowire.Build(DefaultSet, NewStdLogger, autowire.Bind(new(Logger), new(*StdLogger)))
+	return nil
+}
+
+func injectImplicit() *Server {
+	// The only provided type that implements Logger takes precedence over the
+	// default.
+	autowire.Build(DefaultSet, NewStdLogger)
+	return nil
+}
+
+func injectAmbiguous() *AuditedServer {
+	// Both *StdLogger and *FileLogger implement Logger, so the default is used
+	// instead of failing.
+	autowire.Build(DefaultSet, NewStdLogger, NewFileLogger, NewAuditedServer)
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/dabbertorres/autowire"
+)
+
+func main() {
+	fmt.Println(injectDefaultOnly().Run())
+	fmt.Println(injectBound().Run())
+	fmt.Println(injectImplicit().Run())
+	fmt.Println(injectAmbiguous().Run())
+}
+
+type Logger interface {
+	Log(msg string) string
+}
+
+type NopLogger struct{}
+
+func (NopLogger) Log(string) string {
+	return "nop"
+}
+
+// NewNopLogger returns *NopLogger, which is only used as the default for
+// Logger, not as a candidate for an implicit binding.
+func NewNopLogger() *NopLogger {
+	return new(NopLogger)
+}
+
+type StdLogger struct{}
+
+func (*StdLogger) Log(msg string) string {
+	return "std: " + msg
+}
+
+func NewStdLogger() *StdLogger {
+	return new(StdLogger)
+}
+
+type FileLogger struct{}
+
+func (*FileLogger) Log(msg string) string {
+	return "file: " + msg
+}
+
+func NewFileLogger() *FileLogger {
+	return new(FileLogger)
+}
+
+type Server struct {
+	logger Logger
+}
+
+func (s *Server) Run() string {
+	return s.logger.Log("running")
+}
+
+func NewServer(logger Logger) *Server {
+	return &Server{logger: logger}
+}
+
+type AuditedServer struct {
+	*Server
+}
+
+func NewAuditedServer(s *Server, std *StdLogger, file *FileLogger) *AuditedServer {
+	return &AuditedServer{Server: s}
+}
+
+var DefaultSet = autowire.NewSet(
+	autowire.Default(new(Logger), NewNopLogger),
+	NewServer)
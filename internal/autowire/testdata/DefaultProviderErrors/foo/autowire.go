@@ -0,0 +1,47 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+func injectNotInterface() *Server {
+	panic(autowire.Build(autowire.Default(new(NopLogger), NewNopLogger), NewServer))
+}
+
+func injectNotPointer() *Server {
+	panic(autowire.Build(autowire.Default(Logger(nil), NewNopLogger), NewServer))
+}
+
+func injectNotImplemented() *Server {
+	panic(autowire.Build(autowire.Default(new(Logger), NewConfig), NewServer))
+}
+
+func injectStructProvider() *Server {
+	panic(autowire.Build(autowire.Default(new(Logger), autowire.Struct(new(PrefixLogger), "*")), NewServer))
+}
+
+func injectConflict() *Server {
+	panic(autowire.Build(NopSet, PrefixSet, NewServer))
+}
+
+func injectMissingInput() *Server {
+	// The default provider's inputs must still be provided.
+	panic(autowire.Build(PrefixSet, NewServer))
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/dabbertorres/autowire"
+)
+
+func main() {
+	fmt.Println(injectNotInterface())
+}
+
+type Logger interface {
+	Log(msg string) string
+}
+
+type NopLogger struct{}
+
+func (NopLogger) Log(string) string {
+	return "nop"
+}
+
+func NewNopLogger() NopLogger {
+	return NopLogger{}
+}
+
+type Prefix string
+
+type PrefixLogger struct {
+	Prefix Prefix
+}
+
+func (l *PrefixLogger) Log(msg string) string {
+	return string(l.Prefix) + msg
+}
+
+func NewPrefixLogger(prefix Prefix) *PrefixLogger {
+	return &PrefixLogger{Prefix: prefix}
+}
+
+type Config struct{}
+
+func NewConfig() *Config {
+	return new(Config)
+}
+
+type Server struct {
+	logger Logger
+}
+
+func NewServer(logger Logger) *Server {
+	return &Server{logger: logger}
+}
+
+var NopSet = autowire.NewSet(autowire.Default(new(Logger), NewNopLogger))
+
+var PrefixSet = autowire.NewSet(autowire.Default(new(Logger), NewPrefixLogger))
@@ -0,0 +1,46 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"fmt"
+
+	"example.com/bar"
+	"example.com/baz"
+	"example.com/foo"
+	"example.com/qux"
+	"github.com/dabbertorres/autowire"
+)
+
+func newBazService(*foo.Config) *baz.Service {
+	// bar.New and qux.New both depend on the *foo.Service built by foo.New;
+	// qux.New reaches it through an implicit interface binding.
+	autowire.Build(
+		autowire.Struct(new(baz.Service), "*"),
+		foo.New,
+		bar.New,
+		qux.New,
+	)
+	return nil
+}
+
+func main() {
+	svc := newBazService(&foo.Config{V: 1})
+	fmt.Println(svc.String())
+	fmt.Println(foo.Calls)
+}
@@ -30,3 +30,20 @@ func blockComment() *Foo {
 func lineComment() *Bar {
 	panic(autowire.Build(autowire.Struct(new(Bar))))
 }
+
+// multiLineComment returns Baz.
+//
+// Its doc comment spans several lines, and the comment in its body is not
+// copied to the generated injector.
+func multiLineComment() *Baz {
+	// This comment is part of the injector template only.
+	panic(autowire.Build(autowire.Struct(new(Baz))))
+}
+
+/*
+multiLineBlockComment returns Baz and has a
+multi-line block doc comment.
+*/
+func multiLineBlockComment() *Baz {
+	panic(autowire.Build(autowire.Struct(new(Baz))))
+}
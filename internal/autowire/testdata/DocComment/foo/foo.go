@@ -20,6 +20,7 @@ import (
 
 type (
 	Bar struct{}
+	Baz struct{}
 	Foo struct{}
 )
 
@@ -20,3 +20,21 @@ func lineComment() *Bar {
 	bar := &Bar{}
 	return bar
 }
+
+// multiLineComment returns Baz.
+//
+// Its doc comment spans several lines, and the comment in its body is not
+// copied to the generated injector.
+func multiLineComment() *Baz {
+	baz := &Baz{}
+	return baz
+}
+
+/*
+multiLineBlockComment returns Baz and has a
+multi-line block doc comment.
+*/
+func multiLineBlockComment() *Baz {
+	baz := &Baz{}
+	return baz
+}
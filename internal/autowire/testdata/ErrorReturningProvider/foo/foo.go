@@ -0,0 +1,32 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package foo
+
+import "fmt"
+
+type Config struct {
+	V int
+}
+
+type Service struct {
+	Cfg *Config
+}
+
+func New(cfg *Config) (*Service, func(), error) {
+	if cfg.V < 0 {
+		return nil, nil, fmt.Errorf("foo: invalid value %d", cfg.V)
+	}
+	return &Service{Cfg: cfg}, func() { fmt.Println("cleanup foo") }, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"fmt"
+
+	"example.com/bar"
+	"example.com/baz"
+	"example.com/foo"
+	"github.com/dabbertorres/autowire"
+)
+
+func newBarService(*bar.Config, *foo.Service) (*bar.Service, error) {
+	autowire.Build(bar.New)
+	return nil, nil
+}
+
+func newBazService(*baz.Config) (*baz.Service, func(), error) {
+	autowire.Build(
+		autowire.Struct(new(baz.Service), "*"),
+		autowire.FieldsOf(new(*baz.Config), "Foo", "Bar"),
+		foo.New,
+		bar.New,
+	)
+	return nil, nil, nil
+}
+
+func main() {
+	if _, err := newBarService(&bar.Config{-1}, nil); err != nil {
+		fmt.Println(err)
+	}
+
+	// bar.New fails, so the cleanup from foo.New must run before returning.
+	_, cleanup, err := newBazService(&baz.Config{
+		Foo: &foo.Config{1},
+		Bar: &bar.Config{-2},
+	})
+	fmt.Println(err, cleanup == nil)
+
+	svc, cleanup, err := newBazService(&baz.Config{
+		Foo: &foo.Config{1},
+		Bar: &bar.Config{2},
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(svc.String())
+	cleanup()
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "fmt"
+
+func main() {
+	cfg := &Config{
+		Common:   Common{Name: "app"},
+		Database: &Database{DB: &DBConfig{DSN: "mem"}},
+		Addr:     ":80",
+	}
+	s := injectServer(cfg)
+	fmt.Println(s.db.DSN, s.addr)
+	fmt.Println(injectName(*cfg))
+}
+
+type DBConfig struct {
+	DSN string
+}
+
+type Common struct {
+	Name string
+}
+
+type Database struct {
+	DB *DBConfig
+}
+
+// Config embeds Common by value and Database by pointer. Their fields are
+// promoted to Config.
+type Config struct {
+	Common
+	*Database
+	Addr string
+}
+
+type Server struct {
+	db   *DBConfig
+	addr string
+}
+
+func NewServer(db *DBConfig, addr string) *Server {
+	return &Server{db: db, addr: addr}
+}
@@ -0,0 +1,33 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+func injectPort() int {
+	// Port is promoted from Server, within the maximum depth of 2.
+	panic(autowire.Build(NewConfig, autowire.FieldsOf(new(*Config), "Port")))
+}
+
+func injectAddr() string {
+	// fail: Addr is promoted from HTTP through Server, beyond the maximum
+	// depth of 2.
+	panic(autowire.Build(NewConfig, autowire.FieldsOf(new(*Config), "Addr")))
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"fmt"
+
+	"example.com/bar"
+	"example.com/baz"
+	"example.com/foo"
+	"github.com/dabbertorres/autowire"
+)
+
+func newBazService(*baz.Config) *baz.Service {
+	autowire.Build(
+		autowire.Struct(new(baz.Service), "*"),
+		// Only Foo is provided, so Bar's type has no provider even though
+		// baz.Config has a Bar field.
+		autowire.FieldsOf(
+			new(*baz.Config),
+			"Foo",
+		),
+		foo.New,
+		bar.New,
+	)
+	return nil
+}
+
+func main() {
+	cfg := &baz.Config{
+		Foo: &foo.Config{1},
+		Bar: &bar.Config{2},
+	}
+	svc := newBazService(cfg)
+	fmt.Println(svc.String())
+}
@@ -0,0 +1,41 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+func injectApp() *App {
+	autowire.Build(
+		newBundle,
+		// Both Primary and Replica are *DB, so Replica is qualified for the
+		// second parameter of NewReporter, and Primary is the only *DB.
+		autowire.FieldsOf(new(Bundle), "*", "Replica=replica"),
+		NewService,
+		autowire.Qualify(NewReporter, "", "replica"),
+		NewApp,
+	)
+	return nil
+}
+
+func injectReporter() *Reporter {
+	// The fields that nothing needs are not called unused.
+	autowire.Build(newBundle, autowire.FieldsOf(new(Bundle), "*", "-Primary", "Replica=replica"), autowire.Qualify(NewReporter, "", "replica"))
+	return nil
+}
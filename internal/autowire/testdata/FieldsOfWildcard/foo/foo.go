@@ -0,0 +1,87 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+)
+
+func main() {
+	app := injectApp()
+	fmt.Println(app.service.Describe())
+	fmt.Println(app.reporter.Describe())
+	fmt.Println(injectReporter().Describe())
+}
+
+type A struct{ name string }
+
+type B struct{ name string }
+
+type DB struct{ name string }
+
+type Bundle struct {
+	A       *A
+	B       *B
+	Primary *DB
+	Replica *DB
+}
+
+var bundles int
+
+func newBundle() Bundle {
+	bundles++
+	return Bundle{
+		A:       &A{name: "a"},
+		B:       &B{name: "b"},
+		Primary: &DB{name: "primary"},
+		Replica: &DB{name: "replica"},
+	}
+}
+
+type Service struct {
+	a  *A
+	b  *B
+	db *DB
+}
+
+func NewService(a *A, b *B, db *DB) *Service {
+	return &Service{a: a, b: b, db: db}
+}
+
+func (s *Service) Describe() string {
+	return fmt.Sprintf("service: %s %s %s (bundles: %d)", s.a.name, s.b.name, s.db.name, bundles)
+}
+
+type Reporter struct {
+	a  *A
+	db *DB
+}
+
+func NewReporter(a *A, replica *DB) *Reporter {
+	return &Reporter{a: a, db: replica}
+}
+
+func (r *Reporter) Describe() string {
+	return fmt.Sprintf("reporter: %s %s", r.a.name, r.db.name)
+}
+
+type App struct {
+	service  *Service
+	reporter *Reporter
+}
+
+func NewApp(s *Service, r *Reporter) *App {
+	return &App{service: s, reporter: r}
+}
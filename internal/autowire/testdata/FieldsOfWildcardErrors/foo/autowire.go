@@ -0,0 +1,42 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+func injectSameType() *DB {
+	autowire.Build(newBundle, autowire.FieldsOf(new(Bundle), "*"))
+	return nil
+}
+
+func injectRedundantField() *DB {
+	autowire.Build(newBundle, autowire.FieldsOf(new(Bundle), "*", "Primary"))
+	return nil
+}
+
+func injectExcludeWithoutWildcard() *DB {
+	autowire.Build(newBundle, autowire.FieldsOf(new(Bundle), "Primary", "-Replica"))
+	return nil
+}
+
+func injectUnused() *Other {
+	autowire.Build(newBundle, newOther, autowire.FieldsOf(new(Bundle), "*", "-Replica"))
+	return nil
+}
@@ -14,22 +14,22 @@
 
 package main
 
-import "fmt"
-
 func main() {
-	fmt.Println(injectFooer().Foo())
 }
 
-type Fooer interface {
-	Foo() string
-}
+type DB struct{}
 
-type Bar string
+type Bundle struct {
+	Primary *DB
+	Replica *DB
+}
 
-func (b Bar) Foo() string {
-	return string(b)
+func newBundle() Bundle {
+	return Bundle{}
 }
 
-func provideBar() Bar {
-	return "Hello, World!"
+type Other struct{}
+
+func newOther() *Other {
+	return new(Other)
 }
@@ -0,0 +1,81 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+func main() {
+	s, cleanup, err := injectServer()
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	defer cleanup()
+	fmt.Println(s)
+	fmt.Println(injectPlainServer())
+	fmt.Println(injectEmptyServer())
+}
+
+type Config struct {
+	Addr    string
+	Timeout time.Duration
+}
+
+func NewConfig() *Config {
+	return &Config{Addr: ":8080", Timeout: 5 * time.Second}
+}
+
+type Logger struct {
+	Prefix string
+}
+
+func NewLogger() (*Logger, func(), error) {
+	return &Logger{Prefix: "srv"}, func() { fmt.Println("logger closed") }, nil
+}
+
+type Option func(*Server)
+
+type Server struct {
+	addr    string
+	timeout time.Duration
+	logger  *Logger
+}
+
+func NewServer(cfg *Config, opts ...Option) *Server {
+	s := &Server{addr: cfg.Addr}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Server) String() string {
+	logger := "none"
+	if s.logger != nil {
+		logger = s.logger.Prefix
+	}
+	return fmt.Sprintf("server %s timeout=%v logger=%s", s.addr, s.timeout, logger)
+}
+
+func WithTimeout(cfg *Config) Option {
+	return func(s *Server) { s.timeout = cfg.Timeout }
+}
+
+func WithLogger(logger *Logger) Option {
+	return func(s *Server) { s.logger = logger }
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+func injectNotPointer() *Server {
+	panic(autowire.Build(autowire.Options(Option(nil), WithDefaults), NewServer))
+}
+
+func injectWrongType() *Server {
+	panic(autowire.Build(autowire.Options(new(Option), NewName), NewServer))
+}
+
+func injectNotFunction() *Server {
+	panic(autowire.Build(autowire.Options(new(Option), autowire.Struct(new(Server))), NewServer))
+}
+
+func injectListedTwice() *Server {
+	panic(autowire.Build(autowire.Options(new(Option), WithDefaults, WithDefaults), NewServer))
+}
+
+func injectUnused() string {
+	panic(autowire.Build(NewName, autowire.Options(new(Option), WithDefaults)))
+}
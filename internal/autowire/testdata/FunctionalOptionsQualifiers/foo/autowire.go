@@ -0,0 +1,36 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build wireinject
+// +build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+func injectServer() *Server {
+	// The option providers' names do not qualify anything else, so the
+	// provider named WithTimeout fills in the tagged field and the qualified
+	// parameter, and the option is only passed to NewServer.
+	panic(autowire.Build(
+		NewConfig,
+		autowire.Options(new(Option), WithTimeout),
+		autowire.Named("WithTimeout", NewDefaultOption),
+		autowire.Qualify(NewFallback, "WithTimeout"),
+		autowire.Struct(new(Defaults), "*"),
+		NewServer,
+	))
+}
@@ -0,0 +1,77 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+func main() {
+	s := injectServer()
+	fmt.Println(s)
+}
+
+type Config struct {
+	Timeout time.Duration
+}
+
+func NewConfig() *Config {
+	return &Config{Timeout: 5 * time.Second}
+}
+
+type Option func(*Server)
+
+type Server struct {
+	timeout  time.Duration
+	defaults *Defaults
+	fallback Fallback
+}
+
+type Defaults struct {
+	Opt Option `autowire:"WithTimeout"`
+}
+
+type Fallback struct {
+	Opt Option
+}
+
+func NewDefaultOption() Option {
+	return func(s *Server) { s.timeout = time.Second }
+}
+
+func NewFallback(opt Option) Fallback {
+	return Fallback{Opt: opt}
+}
+
+func NewServer(cfg *Config, defaults *Defaults, fallback Fallback, opts ...Option) *Server {
+	s := &Server{defaults: defaults, fallback: fallback}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Server) String() string {
+	d := &Server{}
+	s.defaults.Opt(d)
+	f := &Server{}
+	s.fallback.Opt(f)
+	return fmt.Sprintf("timeout=%v default=%v fallback=%v", s.timeout, d.timeout, f.timeout)
+}
+
+func WithTimeout(cfg *Config) Option {
+	return func(s *Server) { s.timeout = cfg.Timeout }
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache provides a generic cache.
+package cache
+
+type Config struct {
+	Size int
+}
+
+type Cache[T any] struct {
+	size  int
+	items []T
+}
+
+func NewCache[T any](cfg *Config) *Cache[T] {
+	return &Cache[T]{size: cfg.Size}
+}
+
+func (c *Cache[T]) Put(v T) {
+	if len(c.items) < c.size {
+		c.items = append(c.items, v)
+	}
+}
+
+func (c *Cache[T]) Len() int {
+	return len(c.items)
+}
+
+type Pair[K comparable, V any] struct {
+	Key K
+	Val V
+}
+
+func NewPair[K comparable, V any](k K, v V) Pair[K, V] {
+	return Pair[K, V]{Key: k, Val: v}
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"example.com/cache"
+)
+
+func main() {
+	app := injectApp()
+	app.Names.Put("a")
+	app.Names.Put("b")
+	app.Counts.Put(1)
+	fmt.Println(app.Names.Len(), app.Counts.Len())
+	fmt.Println(injectPair())
+}
+
+type App struct {
+	Names  *cache.Cache[string]
+	Counts *cache.Cache[int]
+}
+
+type Key string
+
+func provideConfig() *cache.Config {
+	return &cache.Config{Size: 2}
+}
+
+func provideKey() Key {
+	return "answer"
+}
+
+func provideValue() int {
+	return 42
+}
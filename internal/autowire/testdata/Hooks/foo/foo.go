@@ -0,0 +1,81 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+func main() {
+	s, cleanup, err := injectServer("api")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(s.Name())
+	cleanup()
+
+	_, _, err = injectServer("")
+	fmt.Println(err)
+}
+
+type Config struct {
+	name string
+}
+
+func NewConfig(name string) *Config {
+	return &Config{name: name}
+}
+
+func (c *Config) Validate() error {
+	if c.name == "" {
+		return errors.New("config: missing name")
+	}
+	return nil
+}
+
+type DB struct{}
+
+func NewDB(cfg *Config) (*DB, func(), error) {
+	return new(DB), func() { fmt.Println("close db") }, nil
+}
+
+type Server struct {
+	db  *DB
+	cfg *Config
+}
+
+func NewServer(db *DB, cfg *Config) *Server {
+	return &Server{db: db, cfg: cfg}
+}
+
+func (s *Server) Name() string {
+	return "server " + s.cfg.name
+}
+
+type Metrics struct{}
+
+func NewMetrics() *Metrics {
+	return new(Metrics)
+}
+
+func announce(name string) {
+	fmt.Printf("starting %q\n", name)
+}
+
+func register(s *Server, m *Metrics) {
+	fmt.Println("register", s.Name())
+}
@@ -0,0 +1,35 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+func injectFooer() Fooer {
+	// Bar is the only provided type that implements Fooer.
+	autowire.Build(provideBar)
+	return nil
+}
+
+func injectGreeter() *Greeter {
+	// Both Baz and *Baz implement Fooer, but they come from the same struct
+	// provider, so *Baz is used.
+	autowire.Build(provideGreeter, provideMessage, autowire.Struct(new(Baz), "*"))
+	return nil
+}
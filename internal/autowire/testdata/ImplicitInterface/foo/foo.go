@@ -0,0 +1,63 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "fmt"
+
+func main() {
+	fmt.Println(injectFooer().Foo())
+	fmt.Println(injectGreeter().Greet())
+}
+
+type Fooer interface {
+	Foo() string
+}
+
+type Bar string
+
+func (b Bar) Foo() string {
+	return string(b)
+}
+
+func provideBar() Bar {
+	return "Hello, World!"
+}
+
+type Message string
+
+type Baz struct {
+	Msg Message
+}
+
+func (b Baz) Foo() string {
+	return string(b.Msg)
+}
+
+func provideMessage() Message {
+	return "Hello, Baz!"
+}
+
+type Greeter struct {
+	f Fooer
+}
+
+func (g *Greeter) Greet() string {
+	_, isPtr := g.f.(*Baz)
+	return fmt.Sprintf("%s (pointer: %t)", g.f.Foo(), isPtr)
+}
+
+func provideGreeter(f Fooer) *Greeter {
+	return &Greeter{f: f}
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+func injectFooer() Fooer {
+	// fail: both Bar and *Baz implement Fooer.
+	autowire.Build(provideBar, provideBaz)
+	return nil
+}
+
+func injectGreeter() *Greeter {
+	// fail: Greeter needs a Fooer, which both Bar and *Baz implement.
+	autowire.Build(provideGreeter, provideBar, provideBaz)
+	return nil
+}
+
+func injectPointerReceiver() Fooer {
+	// fail: only *Qux implements Fooer, but Qux is provided.
+	autowire.Build(provideQux)
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "fmt"
+
+func main() {
+	fmt.Println(injectFooer().Foo())
+	fmt.Println(injectGreeter().f.Foo())
+	fmt.Println(injectPointerReceiver().Foo())
+}
+
+type Fooer interface {
+	Foo() string
+}
+
+type Bar string
+
+func (b Bar) Foo() string {
+	return string(b)
+}
+
+func provideBar() Bar {
+	return "bar"
+}
+
+type Baz struct{}
+
+func (*Baz) Foo() string {
+	return "baz"
+}
+
+func provideBaz() *Baz {
+	return new(Baz)
+}
+
+type Qux struct{}
+
+func (*Qux) Foo() string {
+	return "qux"
+}
+
+func provideQux() Qux {
+	return Qux{}
+}
+
+type Greeter struct {
+	f Fooer
+}
+
+func provideGreeter(f Fooer) *Greeter {
+	return &Greeter{f: f}
+}
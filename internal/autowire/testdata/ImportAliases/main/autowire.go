@@ -0,0 +1,39 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+
+	"example.com/wiring"
+
+	cfg "example.com/bar/config"
+	fooconfig "example.com/foo/config"
+)
+
+func injectApp() *App {
+	// The generated file imports the config packages with the same names as
+	// the files of this package, and example.com/qux/config, which no file
+	// imports, as quxconfig.
+	autowire.Build(fooconfig.New, cfg.New, wiring.Set, NewApp)
+	return nil
+}
+
+func describe(c *cfg.Config) string {
+	return "described " + c.Name
+}
@@ -0,0 +1,40 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	bar "example.com/bar/config"
+	"example.com/baz/config"
+	foo "example.com/foo/config"
+	"example.com/wiring"
+)
+
+func main() {
+	fmt.Println(injectApp())
+}
+
+type App struct {
+	names []string
+}
+
+func (a *App) String() string {
+	return fmt.Sprint(a.names)
+}
+
+func NewApp(f *foo.Config, b *bar.Config, z *config.Config, s wiring.Summary) *App {
+	return &App{names: []string{f.Name, describe(b), z.Name, string(s)}}
+}
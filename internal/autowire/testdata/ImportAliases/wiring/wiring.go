@@ -0,0 +1,30 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wiring
+
+import (
+	"github.com/dabbertorres/autowire"
+
+	bazconfig "example.com/baz/config"
+	quxconfig "example.com/qux/config"
+)
+
+type Summary string
+
+func NewSummary(q *quxconfig.Config) Summary {
+	return Summary(q.Name)
+}
+
+var Set = autowire.NewSet(bazconfig.New, quxconfig.New, NewSummary)
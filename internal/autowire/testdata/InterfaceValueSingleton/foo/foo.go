@@ -0,0 +1,41 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+func main() {
+	injectService().Report()
+}
+
+type Clock interface {
+	Now() string
+}
+
+type Service struct {
+	w     io.Writer
+	clock Clock
+}
+
+func NewService(w io.Writer, clock Clock) *Service {
+	return &Service{w: w, clock: clock}
+}
+
+func (s *Service) Report() {
+	fmt.Fprintln(s.w, "it is", s.clock.Now())
+}
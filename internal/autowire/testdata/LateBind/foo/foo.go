@@ -0,0 +1,80 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+)
+
+func main() {
+	hub := injectHub()
+	fmt.Println(hub.Run())
+	fmt.Println(injectImplicitHub().Run())
+	fmt.Println(injectWorker().Report())
+}
+
+type Coordinator interface {
+	Notify(msg string) string
+}
+
+type Worker struct {
+	coordinator Coordinator
+}
+
+func NewWorker() *Worker {
+	return new(Worker)
+}
+
+func (w *Worker) SetCoordinator(c Coordinator) {
+	w.coordinator = c
+}
+
+func (w *Worker) Report() string {
+	return w.coordinator.Notify("done")
+}
+
+// Hub holds the worker and is also its coordinator.
+type Hub struct {
+	worker *Worker
+}
+
+func NewHub(w *Worker) *Hub {
+	return &Hub{worker: w}
+}
+
+func (h *Hub) Notify(msg string) string {
+	return "hub: " + msg
+}
+
+func (h *Hub) Run() string {
+	return h.worker.Report()
+}
+
+type Auditor struct {
+	worker *Worker
+}
+
+func NewAuditor(w *Worker) *Auditor {
+	return &Auditor{worker: w}
+}
+
+func (a *Auditor) Notify(msg string) string {
+	return "auditor: " + msg
+}
+
+// setAuditor is a setter function rather than a method.
+func setAuditor(w *Worker, c Coordinator) {
+	w.SetCoordinator(c)
+}
@@ -0,0 +1,39 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+func injectNotFunc() *Worker {
+	panic(autowire.Build(NewWorker, autowire.LateBind(setter)))
+}
+
+func injectNoInterface() *Worker {
+	panic(autowire.Build(NewWorker, autowire.LateBind((*Worker).Reset)))
+}
+
+func injectNotInterface() *Worker {
+	panic(autowire.Build(NewWorker, autowire.LateBind((*Worker).SetName)))
+}
+
+func injectMissing() *Worker {
+	// Nothing provides the Coordinator for the setter.
+	panic(autowire.Build(NewWorker, autowire.LateBind((*Worker).SetCoordinator)))
+}
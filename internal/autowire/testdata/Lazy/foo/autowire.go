@@ -0,0 +1,39 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+func injectServer() *Server {
+	// The injector itself has no cleanup: the factory returns the cleanup
+	// of each Conn it builds to the caller.
+	panic(autowire.Build(
+		NewDB,
+		autowire.Lazy(NewReport),
+		autowire.Lazy(NewConn),
+		NewServer))
+}
+
+func injectJobs() *Jobs {
+	panic(autowire.Build(
+		NewDB,
+		autowire.Named("audit", autowire.Lazy(NewReport)),
+		autowire.Struct(new(Jobs), "*")))
+}
@@ -0,0 +1,85 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "fmt"
+
+func main() {
+	s := injectServer()
+	r1, err := s.newReport()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	r2, err := s.newReport()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(r1.N, r2.N, r1.DB == r2.DB)
+	conn, cleanup := s.newConn()
+	fmt.Println(conn.DB.Name)
+	cleanup()
+
+	jobs := injectJobs()
+	if _, err := jobs.NewAudit(); err != nil {
+		fmt.Println(err)
+	}
+}
+
+type DB struct {
+	Name string
+}
+
+func NewDB() *DB {
+	fmt.Println("NewDB")
+	return &DB{Name: "main"}
+}
+
+type Report struct {
+	DB *DB
+	N  int
+}
+
+var reports int
+
+func NewReport(db *DB) (*Report, error) {
+	reports++
+	fmt.Println("NewReport")
+	return &Report{DB: db, N: reports}, nil
+}
+
+type Conn struct {
+	DB *DB
+}
+
+func NewConn(db *DB) (*Conn, func()) {
+	fmt.Println("NewConn")
+	return &Conn{DB: db}, func() { fmt.Println("close conn") }
+}
+
+type Server struct {
+	newReport func() (*Report, error)
+	newConn   func() (*Conn, func())
+}
+
+func NewServer(newReport func() (*Report, error), newConn func() (*Conn, func())) *Server {
+	fmt.Println("NewServer")
+	return &Server{newReport: newReport, newConn: newConn}
+}
+
+type Jobs struct {
+	NewAudit func() (*Report, error) `autowire:"audit"`
+}
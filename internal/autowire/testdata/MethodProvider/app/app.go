@@ -0,0 +1,66 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import "fmt"
+
+type Config struct {
+	name string
+	db   DBConfig
+}
+
+func LoadConfig() *Config {
+	return &Config{name: "app", db: DBConfig{DSN: "mem"}}
+}
+
+// Database returns the database configuration.
+func (c *Config) Database() *DBConfig {
+	return &c.db
+}
+
+// Name has a value receiver.
+func (c Config) Name() AppName {
+	return AppName(c.name)
+}
+
+type AppName string
+
+type Cache struct {
+	size int
+}
+
+func (c *Config) Cache() (*Cache, error) {
+	return &Cache{size: 16}, nil
+}
+
+type DBConfig struct {
+	DSN string
+}
+
+type Conn struct {
+	dsn   string
+	name  AppName
+	cache *Cache
+}
+
+func (c *Conn) String() string {
+	return fmt.Sprintf("%s conn to %s with cache of %d", c.name, c.dsn, c.cache.size)
+}
+
+// Open takes the receiver and two more dependencies, and returns a cleanup
+// function and an error.
+func (d *DBConfig) Open(name AppName, cache *Cache) (*Conn, func(), error) {
+	return &Conn{dsn: d.DSN, name: name, cache: cache}, func() { fmt.Println("closed") }, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+// This test verifies that a single Bind can bind a concrete type to several
+// interfaces, and that the concrete type is provided only once for all of them.
+
+package main
+
+import "fmt"
+
+func main() {
+	app := injectApp()
+	app.w.Write("hello")
+	fmt.Println(app.r.Read())
+	fmt.Println(app.r == app.w.(Reader))
+	fmt.Println(newStoreCalls)
+}
+
+type Reader interface {
+	Read() string
+}
+
+type Writer interface {
+	Write(s string)
+}
+
+type Store struct {
+	data string
+}
+
+func (s *Store) Read() string { return s.data }
+
+func (s *Store) Write(data string) { s.data = data }
+
+var newStoreCalls int
+
+func NewStore() *Store {
+	newStoreCalls++
+	return new(Store)
+}
+
+type App struct {
+	r Reader
+	w Writer
+}
+
+func NewApp(r Reader, w Writer) *App {
+	return &App{r: r, w: w}
+}
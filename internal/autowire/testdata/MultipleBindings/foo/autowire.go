@@ -28,21 +28,6 @@ func inject() Foo {
 	panic(autowire.Build(provideFoo, provideFooAgain))
 }
 
-func injectFromSet() Foo {
-	// fail: provideFoo is also provided by Set.
-	panic(autowire.Build(provideFoo, Set))
-}
-
-func injectFromNestedSet() Foo {
-	// fail: provideFoo is also provided by SuperSet, via Set.
-	panic(autowire.Build(provideFoo, SuperSet))
-}
-
-func injectFromSetWithDuplicateBindings() Foo {
-	// fail: DuplicateBindingsSet has two providers for Foo.
-	panic(autowire.Build(SetWithDuplicateBindings))
-}
-
 func injectDuplicateValues() Foo {
 	// fail: provideFoo and autowire.Value both provide Foo.
 	panic(autowire.Build(provideFoo, autowire.Value(Foo("foo"))))
@@ -17,8 +17,6 @@ package main
 import (
 	"io"
 	"strings"
-
-	"github.com/dabbertorres/autowire"
 )
 
 type context struct{}
@@ -28,10 +26,6 @@ func main() {}
 type Foo string
 type Bar io.Reader
 
-var Set = autowire.NewSet(provideFoo)
-var SuperSet = autowire.NewSet(Set)
-var SetWithDuplicateBindings = autowire.NewSet(Set, SuperSet)
-
 func provideFoo() Foo {
 	return Foo("foo")
 }
@@ -0,0 +1,64 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+)
+
+func main() {
+	s := newServer(&Config{Addr: ":8080", Limits: Limits{MaxConns: 10}}, &Secrets{Token: "t0ken"})
+	fmt.Println(s)
+}
+
+type Addr string
+
+type Token string
+
+type Limits struct {
+	MaxConns int
+}
+
+type Config struct {
+	Addr   Addr
+	Limits Limits
+}
+
+type Secrets struct {
+	Token Token
+}
+
+type Auth struct {
+	token Token
+}
+
+func NewAuth(token Token) *Auth {
+	return &Auth{token: token}
+}
+
+type Server struct {
+	addr   Addr
+	limits Limits
+	auth   *Auth
+	cfg    *Config
+}
+
+func (s *Server) String() string {
+	return fmt.Sprintf("%s %d %s %v", s.addr, s.limits.MaxConns, s.auth.token, s.cfg != nil)
+}
+
+func NewServer(addr Addr, limits Limits, auth *Auth, cfg *Config) *Server {
+	return &Server{addr: addr, limits: limits, auth: auth, cfg: cfg}
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dabbertorres/autowire"
+)
+
+func main() {
+	fmt.Println(newFooService().timeout)
+	newBarService().Print("bar")
+	baz := newBazService()
+	fmt.Println(baz.foo.timeout)
+	baz.bar.Print("baz")
+}
+
+type FooService struct {
+	timeout time.Duration
+}
+
+func NewFooService(timeout time.Duration) *FooService {
+	return &FooService{timeout: timeout}
+}
+
+type BarService struct {
+	w io.Writer
+}
+
+func NewBarService(w io.Writer) *BarService {
+	return &BarService{w: w}
+}
+
+func (b *BarService) Print(s string) {
+	fmt.Fprintln(b.w, s)
+}
+
+type BazService struct {
+	foo *FooService
+	bar *BarService
+}
+
+func NewBazService(foo *FooService, bar *BarService) *BazService {
+	return &BazService{foo: foo, bar: bar}
+}
+
+var TimeoutSet = autowire.NewSet(autowire.Value(5 * time.Second))
+
+var OutputSet = autowire.NewSet(autowire.InterfaceValue(new(io.Writer), os.Stdout))
@@ -0,0 +1,58 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/dabbertorres/autowire"
+)
+
+func main() {
+	repo := injectRepo()
+	fmt.Println(repo.primary.name, repo.replica.name)
+	svc := injectService()
+	fmt.Println(svc.Primary.name, svc.Replica.name)
+}
+
+type DB struct {
+	name string
+}
+
+type Repo struct {
+	primary, replica *DB
+}
+
+type Service struct {
+	Primary *DB `autowire:"primary"`
+	Replica *DB `autowire:"replica"`
+}
+
+var DBSet = autowire.NewSet(
+	autowire.Named("primary", NewPrimary),
+	autowire.Named("replica", NewReplica),
+)
+
+func NewPrimary() *DB {
+	return &DB{name: "primary"}
+}
+
+func NewReplica() *DB {
+	return &DB{name: "replica"}
+}
+
+func NewRepo(primary, replica *DB) *Repo {
+	return &Repo{primary: primary, replica: replica}
+}
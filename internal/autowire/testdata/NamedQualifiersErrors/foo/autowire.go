@@ -0,0 +1,44 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+func injectUnqualifiedRepo() *Cache {
+	// NewRepo is not needed, but its parameters still can't be told apart.
+	panic(autowire.Build(autowire.Named("primary", NewPrimary), RepoSet, autowire.Qualify(NewCache, "primary")))
+}
+
+func injectWrongCount() *Repo {
+	panic(autowire.Build(autowire.Named("primary", NewPrimary), autowire.Qualify(NewRepo, "primary")))
+}
+
+func injectMissingQualified() *Repo {
+	panic(autowire.Build(autowire.Named("primary", NewPrimary), autowire.Qualify(NewRepo, "primary", "replica")))
+}
+
+func injectParamName() *Cache {
+	// The parameter of NewCache is named primary, but only Qualify qualifies it.
+	panic(autowire.Build(autowire.Named("primary", NewPrimary), NewCache))
+}
+
+func injectConflict() *Cache {
+	panic(autowire.Build(autowire.Named("primary", NewPrimary), autowire.Named("primary", NewReplica), autowire.Qualify(NewCache, "primary")))
+}
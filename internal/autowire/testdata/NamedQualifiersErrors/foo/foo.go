@@ -0,0 +1,45 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+func main() {}
+
+type DB struct{}
+
+type Repo struct{}
+
+type Cache struct{}
+
+func NewPrimary() *DB {
+	return &DB{}
+}
+
+func NewReplica() *DB {
+	return &DB{}
+}
+
+func NewRepo(primary, replica *DB) *Repo {
+	return &Repo{}
+}
+
+func NewCache(primary *DB) *Cache {
+	return &Cache{}
+}
+
+var RepoSet = autowire.NewSet(NewRepo)
@@ -0,0 +1,43 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	barservice "example.com/bar/service"
+	bazservice "example.com/baz/service"
+	fooservice "example.com/foo/service"
+)
+
+func main() {
+	fmt.Println(injectService())
+}
+
+// Service has the same base name as the type in each of the service
+// packages, which also all have the same package name.
+type Service struct {
+	foo *fooservice.Service
+	bar *barservice.Service
+	baz *bazservice.Service
+}
+
+func (s *Service) String() string {
+	return s.foo.Name + " " + s.bar.Name + " " + s.baz.Name
+}
+
+func NewService(foo *fooservice.Service, bar *barservice.Service, baz *bazservice.Service) *Service {
+	return &Service{foo: foo, bar: bar, baz: baz}
+}
@@ -8,13 +8,13 @@
 package main
 
 import (
-	context2 "context"
+	stdcontext "context"
 )
 
 // Injectors from autowire.go:
 
-func inject(context3 context2.Context, err2 struct{}) (context, error) {
-	mainContext, err := provide(context3)
+func inject(context2 stdcontext.Context, err2 struct{}) (context, error) {
+	mainContext, err := provide(context2)
 	if err != nil {
 		return context{}, err
 	}
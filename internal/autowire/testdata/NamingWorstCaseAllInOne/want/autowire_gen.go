@@ -8,7 +8,7 @@
 package main
 
 import (
-	context2 "context"
+	stdcontext "context"
 	"fmt"
 	"os"
 	"reflect"
@@ -16,8 +16,8 @@ import (
 
 // Injectors from foo.go:
 
-func inject(context3 context2.Context, err2 struct{}) (context, error) {
-	mainContext, err := Provide(context3)
+func inject(context2 stdcontext.Context, err2 struct{}) (context, error) {
+	mainContext, err := Provide(context2)
 	if err != nil {
 		return context{}, err
 	}
@@ -33,7 +33,7 @@ func main() {
 		fmt.Println("ERROR: context.Provide renamed")
 		os.Exit(1)
 	}
-	c, err := inject(context2.Background(), struct{}{})
+	c, err := inject(stdcontext.Background(), struct{}{})
 	if err != nil {
 		fmt.Println("ERROR:", err)
 		os.Exit(1)
@@ -41,9 +41,9 @@ func main() {
 	fmt.Println(c)
 }
 
-func Provide(context2_2 context2.Context) (context, error) {
-	var context3 = context2.Background()
-	_ = context2_2
+func Provide(context2 stdcontext.Context) (context, error) {
+	var context3 = stdcontext.Background()
+	_ = context2
 	_ = context3
 	return context{}, nil
 }
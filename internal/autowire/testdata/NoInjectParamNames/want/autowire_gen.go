@@ -8,12 +8,12 @@
 package main
 
 import (
-	context2 "context"
+	stdcontext "context"
 )
 
 // Injectors from autowire.go:
 
-func inject(contextContext context2.Context, arg struct{}) (context, error) {
+func inject(contextContext stdcontext.Context, arg struct{}) (context, error) {
 	mainContext, err := provide(contextContext)
 	if err != nil {
 		return context{}, err
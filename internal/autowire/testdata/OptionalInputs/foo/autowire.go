@@ -0,0 +1,46 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+var clientSet = autowire.NewSet(
+	NewConfig,
+	autowire.Optional(NewClient, new(Tracer), new([]string)),
+)
+
+func injectClient() *Client {
+	panic(autowire.Build(clientSet))
+}
+
+func injectTracedClient() *Client {
+	panic(autowire.Build(
+		clientSet,
+		autowire.InterfaceValue(new(Tracer), prefixTracer{}),
+		autowire.Value([]string{"gzip"}),
+	))
+}
+
+func injectServer() *Server {
+	panic(autowire.Build(
+		clientSet,
+		autowire.Optional(autowire.Struct(new(Server), "*"), new(*Metrics)),
+	))
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func main() {
+	c := injectClient()
+	fmt.Println(c.Describe())
+	traced := injectTracedClient()
+	fmt.Println(traced.Describe())
+	s := injectServer()
+	fmt.Println(s.Client.Describe(), s.Metrics == nil)
+}
+
+type Config struct {
+	Name string
+}
+
+func NewConfig() *Config {
+	return &Config{Name: "api"}
+}
+
+type Tracer interface {
+	Trace(msg string) string
+}
+
+type prefixTracer struct{}
+
+func (prefixTracer) Trace(msg string) string {
+	return "traced " + msg
+}
+
+type Metrics struct{}
+
+type Client struct {
+	cfg     *Config
+	tracer  Tracer
+	headers []string
+}
+
+func NewClient(cfg *Config, tracer Tracer, headers []string) *Client {
+	return &Client{cfg: cfg, tracer: tracer, headers: headers}
+}
+
+func (c *Client) Describe() string {
+	msg := c.cfg.Name
+	if len(c.headers) > 0 {
+		msg += " [" + strings.Join(c.headers, ", ") + "]"
+	}
+	if c.tracer == nil {
+		return msg + " without tracer"
+	}
+	return c.tracer.Trace(msg)
+}
+
+type Server struct {
+	Client  *Client
+	Metrics *Metrics
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/dabbertorres/autowire"
+)
+
+func injectNoTypes() *Client {
+	panic(autowire.Build(autowire.Value(Config{}), autowire.Optional(NewClient)))
+}
+
+func injectNotNillable() *Client {
+	panic(autowire.Build(autowire.Optional(NewClient, new(Config), new(Tracer))))
+}
+
+func injectNotAnInput() *Client {
+	panic(autowire.Build(autowire.Value(Config{}), autowire.Optional(NewClient, new(*Cache))))
+}
+
+func injectNotPointer() *Client {
+	panic(autowire.Build(autowire.Value(Config{}), autowire.Optional(NewClient, Config{})))
+}
+
+func injectRequiredMissing() *Service {
+	// Only the tracer is optional: the missing cache is still an error.
+	panic(autowire.Build(
+		autowire.Value(Config{}),
+		autowire.Optional(NewClient, new(Tracer)),
+		NewService,
+	))
+}
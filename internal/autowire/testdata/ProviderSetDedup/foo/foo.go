@@ -0,0 +1,58 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/dabbertorres/autowire"
+)
+
+func main() {
+	fmt.Println(injectFromSet())
+	fmt.Println(injectFromNestedSet())
+	fb := injectFromOverlappingSets()
+	fmt.Println(fb.Foo, fb.Bar.Bar())
+}
+
+type Foo string
+
+type Barer interface {
+	Bar() string
+}
+
+type Bar struct {
+	Foo Foo
+}
+
+func (b *Bar) Bar() string {
+	return "bar " + string(b.Foo)
+}
+
+type FooBar struct {
+	Foo Foo
+	Bar Barer
+}
+
+var (
+	Set       = autowire.NewSet(provideFoo)
+	SuperSet  = autowire.NewSet(Set)
+	BarSet    = autowire.NewSet(autowire.Struct(new(Bar), "*"), autowire.Bind(new(Barer), new(*Bar)))
+	FooBarSet = autowire.NewSet(BarSet, autowire.Struct(new(Bar), "*"), autowire.Bind(new(Barer), new(*Bar)), autowire.Struct(new(FooBar), "*"))
+)
+
+func provideFoo() Foo {
+	return Foo("foo")
+}
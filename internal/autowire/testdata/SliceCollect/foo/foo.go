@@ -0,0 +1,72 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/dabbertorres/autowire"
+)
+
+func main() {
+	m := injectMux()
+	for _, h := range m.handlers {
+		fmt.Println(h.Handle("req"))
+	}
+	fmt.Println(len(injectEmpty()))
+}
+
+type Handler interface {
+	Handle(s string) string
+}
+
+type Ping struct{}
+
+func (Ping) Handle(s string) string {
+	return "ping " + s
+}
+
+type Status struct{}
+
+func (*Status) Handle(s string) string {
+	return "status " + s
+}
+
+type Plugin interface {
+	Load()
+}
+
+type Mux struct {
+	handlers []Handler
+}
+
+func NewMux(handlers []Handler) *Mux {
+	return &Mux{handlers: handlers}
+}
+
+var Set = autowire.NewSet(
+	providePing,
+	provideStatus,
+	autowire.Collect(new([]Handler)),
+	NewMux,
+)
+
+func providePing() Ping {
+	return Ping{}
+}
+
+func provideStatus() *Status {
+	return new(Status)
+}
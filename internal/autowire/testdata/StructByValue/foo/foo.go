@@ -0,0 +1,60 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/dabbertorres/autowire"
+)
+
+func main() {
+	fmt.Println(injectConfig().Timeout)
+	fmt.Println(injectConfigPtr().Timeout)
+	s := injectService()
+	fmt.Println(s.cfg.Timeout, s.client.cfg.Timeout)
+}
+
+type Timeout int
+
+func provideTimeout() Timeout {
+	return 30
+}
+
+// Config is a small immutable struct that consumers take by value.
+type Config struct {
+	Timeout Timeout
+}
+
+type Client struct {
+	cfg *Config
+}
+
+// NewClient takes a pointer to the Config provided by the struct provider.
+func NewClient(cfg *Config) *Client {
+	return &Client{cfg: cfg}
+}
+
+type Service struct {
+	cfg    Config
+	client *Client
+}
+
+// NewService takes the Config by value.
+func NewService(cfg Config, client *Client) *Service {
+	return &Service{cfg: cfg, client: client}
+}
+
+var Set = autowire.NewSet(provideTimeout, autowire.Struct(new(Config), "*"))
@@ -0,0 +1,57 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+)
+
+func main() {
+	s := injectService()
+	fmt.Println(s.Foo.Foo(), s.Bar.Bar())
+}
+
+type fooer interface {
+	Foo() string
+}
+
+type barer interface {
+	Bar() string
+}
+
+type Service struct {
+	Foo fooer
+	Bar barer
+}
+
+type Foo struct{}
+
+func NewFoo() *Foo {
+	return new(Foo)
+}
+
+func (*Foo) Foo() string {
+	return "foo"
+}
+
+type Bar struct{}
+
+func NewBar() *Bar {
+	return new(Bar)
+}
+
+func (*Bar) Bar() string {
+	return "bar"
+}
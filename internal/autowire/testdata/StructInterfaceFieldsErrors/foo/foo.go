@@ -0,0 +1,52 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+func main() {
+	injectService()
+}
+
+type fooer interface {
+	Foo() string
+}
+
+type barer interface {
+	Bar() string
+}
+
+type Service struct {
+	Foo fooer
+	Bar barer
+}
+
+type Bar struct{}
+
+func NewBar() *Bar {
+	return new(Bar)
+}
+
+func (*Bar) Bar() string {
+	return "bar"
+}
+
+type OtherBar struct{}
+
+func NewOtherBar() *OtherBar {
+	return new(OtherBar)
+}
+
+func (*OtherBar) Bar() string {
+	return "other bar"
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+func main() {}
+
+type Config struct {
+	Timeout int
+}
+
+func newConfig() *Config {
+	return &Config{Timeout: 30}
+}
+
+type Service struct {
+	cfg Config
+}
+
+func NewService(cfg Config) *Service {
+	return &Service{cfg: cfg}
+}
+
+type Timeout int
+
+func provideTimeout() Timeout {
+	return 30
+}
+
+type Client struct {
+	timeout *Timeout
+}
+
+func NewClient(timeout *Timeout) *Client {
+	return &Client{timeout: timeout}
+}
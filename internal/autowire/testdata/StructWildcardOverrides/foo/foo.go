@@ -0,0 +1,82 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/dabbertorres/autowire"
+)
+
+func main() {
+	app := injectApp()
+	fmt.Println(app.Name, app.Primary.Get(), app.Cache.Get(), app.Replica.Get(), app.Debug)
+	some := injectSomeFields()
+	fmt.Println(some.Name == "", some.Primary == nil, some.Cache.Get())
+}
+
+type Name string
+
+type Store interface {
+	Get() string
+}
+
+type MemStore struct{ name string }
+
+func (m *MemStore) Get() string {
+	return m.name
+}
+
+type DiskStore struct{}
+
+func (*DiskStore) Get() string {
+	return "disk"
+}
+
+type App struct {
+	Name Name
+	// Both *MemStore and *DiskStore implement Store, so the tags choose
+	// which one is used for each field unless Struct overrides them.
+	Primary Store `autowire:"DiskStore"`
+	Cache   Store `autowire:"DiskStore"`
+	Replica Store `autowire:"backup"`
+	Debug   bool
+}
+
+var Set = autowire.NewSet(
+	provideName,
+	provideMemStore,
+	provideDiskStore,
+	autowire.Named("backup", provideBackup),
+	// Fill in every field, but use the memory store for Cache instead of the
+	// one in its tag, and leave Debug as false.
+	autowire.Struct(new(App), "*", "Cache=MemStore", "-Debug"),
+)
+
+func provideName() Name {
+	return "app"
+}
+
+func provideMemStore() *MemStore {
+	return &MemStore{name: "mem"}
+}
+
+func provideDiskStore() *DiskStore {
+	return new(DiskStore)
+}
+
+func provideBackup() Store {
+	return &MemStore{name: "backup"}
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/dabbertorres/autowire"
+)
+
+func main() {
+	app := injectApp()
+	fmt.Println(app.Name, app.Primary.Get(), app.Cache.Get(), app.computed == nil)
+}
+
+type Name string
+
+type Store interface {
+	Get() string
+}
+
+type MemStore struct{}
+
+func (*MemStore) Get() string {
+	return "mem"
+}
+
+type DiskStore struct{}
+
+func (*DiskStore) Get() string {
+	return "disk"
+}
+
+type App struct {
+	Name Name
+	// Store is implemented by both *MemStore and *DiskStore, so the tags
+	// choose which one is used for each field.
+	Primary Store `autowire:"DiskStore"`
+	Cache   Store `autowire:"main.MemStore"`
+
+	computed map[string]string `autowire:"-"`
+}
+
+var Set = autowire.NewSet(
+	provideName,
+	provideMemStore,
+	provideDiskStore,
+	autowire.Struct(new(App), "*"),
+)
+
+func provideName() Name {
+	return "app"
+}
+
+func provideMemStore() *MemStore {
+	return new(MemStore)
+}
+
+func provideDiskStore() *DiskStore {
+	return new(DiskStore)
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/dabbertorres/autowire"
+)
+
+func main() {
+	fmt.Println(injectGreeter().Greet())
+}
+
+type Name string
+
+type Clock interface {
+	Now() string
+}
+
+type RealClock struct{}
+
+func (*RealClock) Now() string {
+	return "now"
+}
+
+type Greeter struct {
+	Name  Name
+	Clock Clock
+}
+
+func (g *Greeter) Greet() string {
+	return fmt.Sprintf("hello %s at %s", g.Name, g.Clock.Now())
+}
+
+var Set = autowire.NewSet(
+	provideName,
+	provideRealClock,
+	autowire.Bind(new(Clock), new(*RealClock)),
+	autowire.Struct(new(Greeter), "*"),
+)
+
+func provideName() Name {
+	return "world"
+}
+
+func provideRealClock() *RealClock {
+	return new(RealClock)
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"example.com/bar"
+	"github.com/dabbertorres/autowire"
+)
+
+func main() {
+	fmt.Println(injectGreeter().Greet())
+	fmt.Println(injectCounter())
+}
+
+type Namer interface {
+	Name() string
+}
+
+type fixedNamer string
+
+func (n fixedNamer) Name() string {
+	return string(n)
+}
+
+type Greeter struct {
+	n Namer
+}
+
+func (g *Greeter) Greet() string {
+	return "Hello, " + g.n.Name()
+}
+
+func NewGreeter(n Namer) *Greeter {
+	return &Greeter{n: n}
+}
+
+func provideNamer() fixedNamer {
+	return "World"
+}
+
+type Counter int
+
+// provideCounter is only used by injectCounter.
+func provideCounter() Counter {
+	return 1
+}
+
+type Config struct {
+	Prefix string
+	Suffix string
+}
+
+func provideUnused() *strings.Builder {
+	return new(strings.Builder)
+}
+
+// NamerSet is only used through the interface binding.
+var NamerSet = autowire.NewSet(
+	provideNamer,
+	autowire.Bind(new(Namer), new(fixedNamer)),
+)
+
+var AppSet = autowire.NewSet(
+	NamerSet,
+	NewGreeter,
+	provideCounter,
+	provideUnused,
+	autowire.Value(Config{}),
+	autowire.FieldsOf(new(Config), "Prefix"),
+	autowire.Bind(new(fmt.Stringer), new(*strings.Builder)),
+	// Members of provider sets declared in other packages are not reported.
+	bar.Set,
+)
@@ -0,0 +1,37 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+func main() {
+	c := injectClient()
+	fmt.Println(c.timeout, c.verbose)
+}
+
+// Verbose is a feature flag for Client.
+type Verbose bool
+
+type Client struct {
+	timeout time.Duration
+	verbose Verbose
+}
+
+func NewClient(timeout time.Duration, verbose Verbose) *Client {
+	return &Client{timeout: timeout, verbose: verbose}
+}
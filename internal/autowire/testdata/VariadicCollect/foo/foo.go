@@ -0,0 +1,90 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/dabbertorres/autowire"
+)
+
+func main() {
+	fmt.Println(injectRouter().Serve("req"))
+	fmt.Println(len(injectHandler().opts))
+}
+
+type Middleware interface {
+	Wrap(s string) string
+}
+
+type Auth struct{}
+
+func (Auth) Wrap(s string) string {
+	return "auth(" + s + ")"
+}
+
+type Logging struct{}
+
+func (*Logging) Wrap(s string) string {
+	return "logging(" + s + ")"
+}
+
+// Metrics is provided as both Metrics and *Metrics by its struct provider,
+// but only *Metrics is passed to NewRouter.
+type Metrics struct{}
+
+func (Metrics) Wrap(s string) string {
+	return "metrics(" + s + ")"
+}
+
+type Router struct {
+	mws []Middleware
+}
+
+func (r *Router) Serve(s string) string {
+	for _, mw := range r.mws {
+		s = mw.Wrap(s)
+	}
+	return s
+}
+
+func NewRouter(mws ...Middleware) *Router {
+	return &Router{mws: mws}
+}
+
+type Option func(*Handler)
+
+type Handler struct {
+	opts []Option
+}
+
+func NewHandler(opts ...Option) *Handler {
+	return &Handler{opts: opts}
+}
+
+var Set = autowire.NewSet(
+	provideAuth,
+	provideLogging,
+	autowire.Struct(new(Metrics)),
+	NewRouter,
+)
+
+func provideAuth() Auth {
+	return Auth{}
+}
+
+func provideLogging() *Logging {
+	return new(Logging)
+}
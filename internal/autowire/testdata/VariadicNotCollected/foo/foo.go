@@ -0,0 +1,73 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+)
+
+func main() {
+	l := injectLogger("log")
+	fmt.Println(len(l.extra))
+	r := injectRouter("req")
+	fmt.Println(len(r.mws), r.fallback.Wrap("req"))
+}
+
+type Config struct{}
+
+func NewConfig() *Config {
+	return new(Config)
+}
+
+type Logger struct {
+	extra []interface{}
+}
+
+func NewLogger(cfg *Config, extra ...interface{}) *Logger {
+	return &Logger{extra: extra}
+}
+
+type Middleware interface {
+	Wrap(s string) string
+}
+
+type Auth struct{}
+
+func (Auth) Wrap(s string) string {
+	return "auth(" + s + ")"
+}
+
+func provideAuth() Auth {
+	return Auth{}
+}
+
+type Fallback struct{}
+
+func (*Fallback) Wrap(s string) string {
+	return "fallback(" + s + ")"
+}
+
+func NewFallback(prefix string) *Fallback {
+	return new(Fallback)
+}
+
+type Router struct {
+	fallback *Fallback
+	mws      []Middleware
+}
+
+func NewRouter(fallback *Fallback, mws ...Middleware) *Router {
+	return &Router{fallback: fallback, mws: mws}
+}
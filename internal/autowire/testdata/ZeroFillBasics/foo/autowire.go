@@ -12,6 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+
 //+build wireinject
 //go:build wireinject
 
@@ -21,7 +22,6 @@ import (
 	"github.com/dabbertorres/autowire"
 )
 
-func injectFooer() Fooer {
-	autowire.Build(provideBar)
-	return nil
+func injectServer() *Server {
+	panic(autowire.Build(NewServer, ProvidePort))
 }
@@ -0,0 +1,39 @@
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+// This test verifies that with GenerateOptions.ZeroFillBasics, inputs of basic
+// types that nothing provides are their zero values.
+
+package main
+
+import "fmt"
+
+func main() {
+	s := injectServer()
+	fmt.Printf("%q %d %t %v\n", s.Addr, s.Port, s.Debug, s.Ratio)
+}
+
+type Server struct {
+	Addr  string
+	Port  int
+	Debug bool
+	Ratio float64
+}
+
+func NewServer(addr string, port int, debug bool, ratio float64) *Server {
+	return &Server{Addr: addr, Port: port, Debug: debug, Ratio: ratio}
+}
+
+func ProvidePort() int { return 8080 }